@@ -6,11 +6,15 @@ import (
 	"github.com/BattlesnakeOfficial/rules/client"
 
 	// "github.com/samber/mo"
+	"context"
 	"fmt"
 	"log"
+	"math/rand"
+	"runtime"
 	// "math"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/samber/lo"
 )
@@ -19,56 +23,193 @@ import (
 type SnakeAgent struct {
 	Portfolio   HeuristicPortfolio
 	Temperature float64
-	Metadata    client.SnakeMetadataResponse
+	// TemperatureSchedule sets the temperature for each decision; when nil, Temperature is used throughout.
+	TemperatureSchedule TemperatureSchedule
+	Metadata            client.SnakeMetadataResponse
+	// SearchDepth is the maximum number of plies of simultaneous moves to look ahead; 1 scores the next turn only.
+	// Deeper plies are searched only while the game's move timeout allows.
+	SearchDepth int
+	// TimeoutMargin is how long before the game's move timeout the search must stop, to leave room for latency.
+	TimeoutMargin time.Duration
+	// Aggregator combines the scores of a move's possible next states, unless a heuristic sets its own.
+	Aggregator Aggregator
+	// OpponentModel gives the probability of each move of every other snake, used to weight next states.
+	OpponentModel OpponentModel
+	// ProximityRadius is the Manhattan distance from our head beyond which a snake's moves are not
+	// enumerated; such snakes play only their most likely safe move. Zero enumerates every snake.
+	ProximityRadius int
+	// Workers bounds the goroutines used to generate and score next states; 1 runs sequentially.
+	Workers int
+	// SafetyChecks veto candidate moves before scoring; if every move is vetoed the least-vetoed moves are kept.
+	SafetyChecks []SafetyCheck
+	// SeedFromGame seeds each decision from the game ID, turn and snake ID instead of the rand source.
+	SeedFromGame bool
+	// PlanAsTeam plans the moves of all our alive snakes jointly, once per turn, when we have teammates.
+	PlanAsTeam bool
+	// AnnounceIntent shouts our next move and target food so teammates can rely on them; see Intent.
+	// Snakes without teammates never announce, since only opponents would read it.
+	AnnounceIntent bool
+	// IntentCommitment is the most probability given to the move we announced for this turn, if it is still
+	// a candidate; the rest is shared out by the softmax. It should match the confidence teammates place in it.
+	IntentCommitment float64
+	// FoodSpawn sets how simulated turns spawn food; by default they spawn none, so evaluations are repeatable.
+	FoodSpawn FoodSpawnMode
+
+	randSource rand.Source
+	teamPlans  *teamPlanCache
+}
+
+// nextState is a possible state of the game after one turn, with the probability of reaching it
+// under the agent's opponent model.
+type nextState struct {
+	snapshot    GameSnapshot
+	probability float64
+}
+
+// moveCombination is one joint move of every alive snake, with its probability under the opponent model.
+type moveCombination struct {
+	moves       map[string]rules.SnakeMove
+	probability float64
 }
 
 func NewSnakeAgentWithTemp(portfolio HeuristicPortfolio, temperature float64, metadata client.SnakeMetadataResponse) *SnakeAgent {
-	return &SnakeAgent{
-		Portfolio:   portfolio,
-		Temperature: temperature,
-		Metadata:    metadata,
+	snakeAgent := &SnakeAgent{
+		Portfolio:        portfolio,
+		Temperature:      temperature,
+		Metadata:         metadata,
+		SearchDepth:      3,
+		TimeoutMargin:    150 * time.Millisecond,
+		Aggregator:       MeanAggregator(),
+		OpponentModel:    IntentAwareOpponentModel(SafeMoveOpponentModel(), defaultIntentConfidence),
+		ProximityRadius:  6,
+		Workers:          runtime.GOMAXPROCS(0),
+		PlanAsTeam:       true,
+		AnnounceIntent:   true,
+		IntentCommitment: defaultIntentConfidence,
+		teamPlans:        newTeamPlanCache(),
 	}
+	snakeAgent.SafetyChecks = []SafetyCheck{OutOfBoundsCheck(), SelfCollisionCheck(), snakeAgent.CertainDeathCheck()}
+	return snakeAgent
 }
 
 func NewSnakeAgent(portfolio HeuristicPortfolio, metadata client.SnakeMetadataResponse) *SnakeAgent {
-	return &SnakeAgent{
-		Portfolio:   portfolio,
-		Temperature: 5.0,
-		Metadata:    metadata,
-	}
+	return NewSnakeAgentWithTemp(portfolio, 5.0, metadata)
 }
 
+// ChooseMove picks the move to send back to the game engine; see Decide for the full breakdown.
 func (sa *SnakeAgent) ChooseMove(snapshot GameSnapshot) client.MoveResponse {
+	return sa.Decide(snapshot).Response()
+}
+
+// Decide scores every forward move with the portfolio and samples one from the softmax of the scores,
+// returning the whole decision so it can be inspected, replayed or tested.
+// With teammates on the board and PlanAsTeam set, the move comes from the team's joint plan instead.
+// With AnnounceIntent set and teammates on the board, the decision also carries the intent to shout to them.
+func (sa *SnakeAgent) Decide(snapshot GameSnapshot) MoveDecision {
+	start := time.Now()
+	snapshot = snapshot.WithFoodSpawn(sa.FoodSpawn)
+	if sa.PlanAsTeam && len(snapshot.YourTeam()) > 1 {
+		return sa.teamDecision(snapshot, start)
+	}
+	return sa.decide(snapshot, sa.nextSeed(snapshot), start, 0)
+}
+
+// DecideWithSeed is Decide with the move sampled by a generator seeded with seed. How deep the search
+// gets depends on the time it is given, so to reproduce a recorded MoveDecision use Replay instead.
+func (sa *SnakeAgent) DecideWithSeed(snapshot GameSnapshot, seed int64) MoveDecision {
+	return sa.decide(snapshot, seed, time.Now(), 0)
+}
+
+// Replay reproduces a recorded MoveDecision for the same snapshot from its Seed and Depth: the search
+// runs to exactly that depth, however long it takes, so the scores and the sampled move come out the same.
+func (sa *SnakeAgent) Replay(snapshot GameSnapshot, seed int64, depth int) MoveDecision {
+	return sa.decide(snapshot, seed, time.Now(), max(depth, 1))
+}
+
+// decide scores and samples a move, searching to replayDepth without a deadline if it is positive,
+// and otherwise as deep as the game's move timeout, counted from start, allows.
+func (sa *SnakeAgent) decide(snapshot GameSnapshot, seed int64, start time.Time, replayDepth int) MoveDecision {
+	snapshot = snapshot.WithFoodSpawn(sa.FoodSpawn)
 	you := snapshot.You()
 	forwardMoves := you.ForwardMoves()
 
 	forwardMoveStrs := lo.Map(forwardMoves, func(move rules.SnakeMove, _ int) string { return move.Move })
 	slices.Sort(forwardMoveStrs)
-	log.Printf("\n\n ### Start Turn %d: Forward Moves = %v", snapshot.Turn(), forwardMoveStrs)
+	log.Printf("\n\n ### Start Turn %d: Forward Moves = %v, Seed = %d", snapshot.Turn(), forwardMoveStrs, seed)
+
+	forwardMoveStrs, vetoes := filterSafeMoves(snapshot, sa.SafetyChecks, forwardMoveStrs)
+	vetoedMoves := lo.Keys(vetoes)
+	slices.Sort(vetoedMoves)
+	for _, move := range vetoedMoves {
+		log.Printf("Vetoed move %s: %v", move, vetoes[move])
+	}
+
+	if fullCount, prunedCount := sa.countPrunedCombinations(snapshot); prunedCount > 0 {
+		log.Printf("Pruned %d of %d opponent move combinations per move for snakes beyond radius %d", prunedCount, fullCount, sa.ProximityRadius)
+	}
 
-	// map: move -> set(state snapshots)
-	nextStatesMap := make(map[string][]GameSnapshot)
+	// map: move -> set(state snapshots with probabilities)
+	nextStatesMap := make(map[string][]nextState)
 	for _, move := range forwardMoveStrs {
-		nextStatesMap[move] = sa.generateNextStates(snapshot, move)
+		nextStatesMap[move] = sa.generateNextStates(snapshot, move, sa.Workers)
 	}
 
-	// slice of maps, for each heuristic, giving mapping: move -> aggScore
-	heuristicScores := lo.Map(sa.Portfolio, func(heuristic WeightedHeuristic, _ int) map[string]float64 {
-		return sa.weightedScoresForHeuristic(heuristic, nextStatesMap, forwardMoveStrs)
-	})
+	ctx, cancel := sa.searchContext(snapshot, start)
+	defer cancel()
+	maxDepth := max(sa.SearchDepth, 1)
+	if replayDepth > 0 {
+		ctx, maxDepth = context.Background(), replayDepth
+	}
+	table := newTranspositionTable()
+
+	// map: move -> per-heuristic scores of each next state, from the deepest search that fit the deadline
+	stateScoresMap, depth := sa.searchNextStates(ctx, table, nextStatesMap, maxDepth, start)
 
-	totalHeuristicWeight := lo.SumBy(sa.Portfolio, func(heuristic WeightedHeuristic) float64 {
-		return heuristic.Weight()
+	hasTeammates := len(snapshot.YourTeam()) > 1
+	committed := ""
+	if intent, announced := AnnouncedIntent(snapshot, you); announced && hasTeammates && sa.IntentCommitment > 0 {
+		committed = intent.Move
+	}
+	decision := sa.sampleMove(snapshot, forwardMoveStrs, nextStatesMap, stateScoresMap, seed, committed)
+	decision.Turn = snapshot.Turn()
+	decision.Vetoes = vetoes
+	decision.Depth = depth
+	if sa.AnnounceIntent && hasTeammates {
+		decision.Intent = sa.intentAfter(ctx, table, snapshot, nextStatesMap[decision.Move], depth)
+	}
+	return decision
+}
+
+// sampleMove ranks the moves by the portfolio's scores of their next states and samples one from the
+// softmax of the ranking with a generator seeded with seed. If committed is one of the moves, it gets
+// up to IntentCommitment of the probability, scaled down by how much less likely the softmax makes it
+// than the best move, so an announcement that no longer looks good is not kept to. The decision's Moves, Heuristics, Scores, Temperature,
+// Probabilities, Seed, Draw and Move are filled in.
+func (sa *SnakeAgent) sampleMove(snapshot GameSnapshot, forwardMoveStrs []string, nextStatesMap map[string][]nextState, stateScoresMap map[string][][]float64, seed int64, committed string) MoveDecision {
+	// for each heuristic, its raw and weighted scores aligned with forwardMoveStrs
+	heuristicBreakdowns := lo.Map(sa.Portfolio, func(heuristic WeightedHeuristic, i int) HeuristicBreakdown {
+		return sa.scoresForHeuristic(heuristic, i, nextStatesMap, stateScoresMap, forwardMoveStrs)
 	})
 
+	weightScale := sa.Portfolio.weightScale()
+
 	// slice of scores aligned with forwardMoveStrs
-	normalizedScores := lo.Map(forwardMoveStrs, func(move string, _ int) float64 {
-		return lo.SumBy(heuristicScores, func(scores map[string]float64) float64 {
-			return scores[move] / totalHeuristicWeight
+	normalizedScores := lo.Map(forwardMoveStrs, func(_ string, i int) float64 {
+		return lo.SumBy(heuristicBreakdowns, func(breakdown HeuristicBreakdown) float64 {
+			return breakdown.WeightedScores[i] / weightScale
 		})
 	})
 
-	probs := lib.SoftmaxWithTemp(normalizedScores, sa.Temperature)
+	temperature := sa.temperatureSchedule().Temperature(snapshot, normalizedScores)
+	probs := lib.SoftmaxWithTemp(normalizedScores, temperature)
+	log.Printf("### %36s: %.2f (%s)", "Temperature", temperature, sa.temperatureSchedule().Name())
+	if index := lo.IndexOf(forwardMoveStrs, committed); index >= 0 {
+		commitment := sa.IntentCommitment * probs[index] / lo.Max(probs)
+		probs = lo.Map(probs, func(p float64, i int) float64 {
+			return (1-commitment)*p + lo.Ternary(i == index, commitment, 0)
+		})
+		log.Printf("### %36s: %s (%.2f)", "Committed to announced move", committed, commitment)
+	}
 
 	log.Printf("### %36s: %s", "Aggregate move weights", strings.Join(lo.Map(forwardMoveStrs, func(move string, i int) string {
 		return fmt.Sprintf("%s=%6.1f", move, normalizedScores[i])
@@ -77,68 +218,160 @@ func (sa *SnakeAgent) ChooseMove(snapshot GameSnapshot) client.MoveResponse {
 		return fmt.Sprintf("%s=%5.1f%%", move, probs[i]*100)
 	}), ", "))
 
-	chosenMove := forwardMoveStrs[lib.SampleFromWeights(probs)]
-
-	return client.MoveResponse{
-		Move:  chosenMove,
-		Shout: "I'm moving " + chosenMove,
+	draw := rand.New(rand.NewSource(seed)).Float64()
+	chosenMove := forwardMoveStrs[lib.SampleFromWeightsWithDraw(probs, draw)]
+
+	return MoveDecision{
+		Moves:         forwardMoveStrs,
+		Heuristics:    heuristicBreakdowns,
+		Scores:        normalizedScores,
+		Temperature:   temperature,
+		Probabilities: probs,
+		Seed:          seed,
+		Draw:          draw,
+		Move:          chosenMove,
 	}
 }
 
-func (sa *SnakeAgent) weightedScoresForHeuristic(heuristic WeightedHeuristic, nextStatesMap map[string][]GameSnapshot, forwardMoveStrs []string) map[string]float64 {
-	moveScores := make(map[string]float64)
-	for move, states := range nextStatesMap {
-		moveScores[move] = lo.MeanBy(states, heuristic.F())
-	}
+// scoresForHeuristic aggregates one heuristic's scores over each move's next states, and weights them.
+func (sa *SnakeAgent) scoresForHeuristic(heuristic WeightedHeuristic, index int, nextStatesMap map[string][]nextState, stateScoresMap map[string][][]float64, forwardMoveStrs []string) HeuristicBreakdown {
+	aggregator := sa.aggregatorFor(heuristic)
+	moveScores := lo.Map(forwardMoveStrs, func(move string, _ int) float64 {
+		return aggregator.Aggregate(
+			lo.Map(stateScoresMap[move], func(scores []float64, _ int) float64 { return scores[index] }),
+			lo.Map(nextStatesMap[move], func(state nextState, _ int) float64 { return state.probability }),
+		)
+	})
 
-	log.Printf("MoveScores for %25s: %s", heuristic.NameAndWeight(), strings.Join(lo.Map(forwardMoveStrs, func(move string, _ int) string {
-		return fmt.Sprintf("%s=%6.1f", move, moveScores[move])
+	log.Printf("MoveScores for %25s: %s", heuristic.NameAndWeight(), strings.Join(lo.Map(forwardMoveStrs, func(move string, i int) string {
+		return fmt.Sprintf("%s=%6.1f", move, moveScores[i])
 	}), ", "))
 
-	weightedScores := lo.MapValues(moveScores, func(score float64, _ string) float64 {
-		return score * heuristic.Weight()
+	normalizedScores := heuristic.Normalization().Apply(moveScores)
+	breakdown := HeuristicBreakdown{
+		Name:             heuristic.Name(),
+		Weight:           heuristic.Weight(),
+		RawScores:        moveScores,
+		NormalizedScores: normalizedScores,
+		WeightedScores: lo.Map(normalizedScores, func(score float64, _ int) float64 {
+			return score * heuristic.Weight()
+		}),
+	}
+	if breakdownHeuristic, ok := heuristic.(BreakdownHeuristic); ok {
+		breakdown.Parts = partsForHeuristic(breakdownHeuristic, aggregator, nextStatesMap, forwardMoveStrs)
+	}
+	return breakdown
+}
+
+// partsForHeuristic aggregates each named part of the heuristic over every move's next states.
+// A part missing from a state's breakdown counts as zero there.
+func partsForHeuristic(heuristic BreakdownHeuristic, aggregator Aggregator, nextStatesMap map[string][]nextState, forwardMoveStrs []string) map[string][]float64 {
+	stateParts := lo.MapValues(nextStatesMap, func(states []nextState, _ string) []map[string]float64 {
+		return lo.Map(states, func(state nextState, _ int) map[string]float64 { return heuristic.Breakdown(state.snapshot) })
 	})
 
-	return weightedScores
+	parts := make(map[string][]float64)
+	for _, move := range forwardMoveStrs {
+		for _, breakdown := range stateParts[move] {
+			for name := range breakdown {
+				parts[name] = nil
+			}
+		}
+	}
+	for name := range parts {
+		parts[name] = lo.Map(forwardMoveStrs, func(move string, _ int) float64 {
+			return aggregator.Aggregate(
+				lo.Map(stateParts[move], func(breakdown map[string]float64, _ int) float64 { return breakdown[name] }),
+				lo.Map(nextStatesMap[move], func(state nextState, _ int) float64 { return state.probability }),
+			)
+		})
+	}
+	return parts
+}
+
+// temperatureSchedule returns the agent's schedule, or a constant schedule at Temperature when none is set.
+func (sa *SnakeAgent) temperatureSchedule() TemperatureSchedule {
+	if sa.TemperatureSchedule != nil {
+		return sa.TemperatureSchedule
+	}
+	return ConstantTemperature(sa.Temperature)
+}
+
+// aggregatorFor returns the heuristic's own aggregator if it has one, and the agent's otherwise.
+func (sa *SnakeAgent) aggregatorFor(heuristic WeightedHeuristic) Aggregator {
+	if heuristic.Aggregator() != nil {
+		return heuristic.Aggregator()
+	}
+	if sa.Aggregator != nil {
+		return sa.Aggregator
+	}
+	return MeanAggregator()
+}
+
+// opponentModel returns the agent's opponent model, defaulting to uniform when none is set.
+func (sa *SnakeAgent) opponentModel() OpponentModel {
+	if sa.OpponentModel != nil {
+		return sa.OpponentModel
+	}
+	return UniformOpponentModel()
 }
 
-func (sa *SnakeAgent) generateNextStates(snapshot GameSnapshot, move string) []GameSnapshot {
-	var nextStates []GameSnapshot
+// generateNextStates applies every likely move combination of the other snakes alongside our move,
+// spreading the work over the given number of workers. States come back in combination order.
+func (sa *SnakeAgent) generateNextStates(snapshot GameSnapshot, move string, workers int) []nextState {
 	yourID := snapshot.You().ID()
+	presetMoves := sa.distantSnakeMoves(snapshot)
+	presetMoves[yourID] = rules.SnakeMove{ID: yourID, Move: move}
+	return sa.applyMoveCombinations(snapshot, presetMoves, workers)
+}
 
-	// Generate all possible move combinations for other snakes
-	presetMoves := map[string]rules.SnakeMove{yourID: {ID: yourID, Move: move}}
-	moveCombinations := generateForwardMoveCombinations(snapshot.Snakes(), presetMoves)
+// applyMoveCombinations applies every likely move combination of the snakes without a preset move
+// alongside the preset moves, spreading the work over the given number of workers.
+func (sa *SnakeAgent) applyMoveCombinations(snapshot GameSnapshot, presetMoves map[string]rules.SnakeMove, workers int) []nextState {
+	// Generate all likely move combinations for nearby snakes
+	moveCombinations := generateForwardMoveCombinations(snapshot.Snakes(), presetMoves, func(snake SnakeSnapshot) []float64 {
+		return sa.opponentModel().MoveProbabilities(snapshot, snake)
+	})
 
 	// log.Printf("Trying move %s, combinations: %v", move, getMoveComboList(moveCombinations))
 
-	for _, combination := range moveCombinations {
+	nextStateSlots := make([]*nextState, len(moveCombinations))
+	lib.ParallelFor(len(moveCombinations), workers, func(i int) {
+		combination := moveCombinations[i]
+
 		// Convert the combination map to a slice
 		var moveSlice []rules.SnakeMove
-		for _, m := range combination {
+		for _, m := range combination.moves {
 			moveSlice = append(moveSlice, m)
 		}
 
 		if snapshot == nil {
 			log.Fatalf("Snapshot is nil before applying moves")
 		}
-		nextState, err := snapshot.ApplyMoves(moveSlice)
+		nextSnapshot, err := snapshot.ApplyMoves(moveSlice)
 
 		if err != nil {
 			log.Fatalf("Error applying moves: %v", err)
 		} else { // Debug the state after ApplyMoves call
-			// log.Printf("Next state after applying move: %+v", nextState)
+			// log.Printf("Next state after applying move: %+v", nextSnapshot)
 		}
-		if nextState != nil {
-			nextStates = append(nextStates, nextState)
+		if nextSnapshot != nil {
+			nextStateSlots[i] = &nextState{snapshot: nextSnapshot, probability: combination.probability}
 		}
-	}
+	})
+
+	nextStates := lo.FilterMap(nextStateSlots, func(state *nextState, _ int) (nextState, bool) {
+		return lo.FromPtr(state), state != nil
+	})
 	// log.Printf("Generated next states: %+v", nextStates)
 
 	return nextStates
 }
 
-func generateForwardMoveCombinations(snakes []SnakeSnapshot, presetMoves map[string]rules.SnakeMove) []map[string]rules.SnakeMove {
+// generateForwardMoveCombinations returns every joint forward move of the snakes without a preset move,
+// combined with the preset moves. Each combination's probability is the product of the per-snake
+// probabilities from moveProbabilities; combinations with zero probability are left out.
+func generateForwardMoveCombinations(snakes []SnakeSnapshot, presetMoves map[string]rules.SnakeMove, moveProbabilities func(SnakeSnapshot) []float64) []moveCombination {
 	presetSnakeIDs := lo.Keys(presetMoves)
 
 	nonPresetSnakes := lo.Filter(snakes, func(snake SnakeSnapshot, _ int) bool {
@@ -147,34 +380,83 @@ func generateForwardMoveCombinations(snakes []SnakeSnapshot, presetMoves map[str
 
 	// If there are no non-preset snakes, return just our preset combination
 	if len(nonPresetSnakes) == 0 {
-		return []map[string]rules.SnakeMove{presetMoves}
+		return []moveCombination{{moves: presetMoves, probability: 1}}
 	}
 
 	nonPresetMoves := lo.Map(nonPresetSnakes, func(snake SnakeSnapshot, _ int) []rules.SnakeMove {
 		return snake.ForwardMoves()
 	})
 
+	// for each non-preset snake, mapping: move -> probability
+	nonPresetProbs := lo.Map(nonPresetSnakes, func(snake SnakeSnapshot, i int) map[string]float64 {
+		probs := moveProbabilities(snake)
+		moveProbs := make(map[string]float64, len(probs))
+		for j, move := range nonPresetMoves[i] {
+			moveProbs[move.Move] = probs[j]
+		}
+		return moveProbs
+	})
+
 	moveCombinations := lib.CartesianProduct(nonPresetMoves...)
 
 	// mix in preset moves to each combo and convert to map from snakeID->move
-	mappedCombinations := make([]map[string]rules.SnakeMove, len(moveCombinations))
+	mappedCombinations := make([]moveCombination, 0)
 	for moveSet := range moveCombinations {
-		combination := lo.Assign(presetMoves)
+		combination := moveCombination{moves: lo.Assign(presetMoves), probability: 1}
 		for j, move := range moveSet {
-			combination[nonPresetSnakes[j].ID()] = move
+			combination.moves[nonPresetSnakes[j].ID()] = move
+			combination.probability *= nonPresetProbs[j][move.Move]
+		}
+		if combination.probability > 0 {
+			mappedCombinations = append(mappedCombinations, combination)
 		}
-		mappedCombinations = append(mappedCombinations, combination)
 	}
 
 	return mappedCombinations
 }
 
+// distantSnakeMoves returns a single representative move for every snake beyond ProximityRadius of our head:
+// its most likely safe move. Those snakes cannot reach us soon, so enumerating their moves adds no information.
+func (sa *SnakeAgent) distantSnakeMoves(snapshot GameSnapshot) map[string]rules.SnakeMove {
+	distantMoves := make(map[string]rules.SnakeMove)
+	if sa.ProximityRadius <= 0 {
+		return distantMoves
+	}
+
+	yourHead := snapshot.You().Head()
+	blocked := blockedNextTurn(snapshot)
+	for _, snake := range snapshot.Snakes() {
+		if snake.ID() == snapshot.You().ID() || manhattanDistance(snapshot, yourHead, snake.Head()) <= sa.ProximityRadius {
+			continue
+		}
+		distantMoves[snake.ID()] = mostLikelySafeMove(snapshot, blocked, snake, sa.opponentModel())
+	}
+	return distantMoves
+}
+
+// countPrunedCombinations returns how many joint moves of the other snakes exist for each of our moves,
+// and how many of them proximity pruning skips.
+func (sa *SnakeAgent) countPrunedCombinations(snapshot GameSnapshot) (int, int) {
+	distantMoves := sa.distantSnakeMoves(snapshot)
+	fullCount, keptCount := 1, 1
+	for _, snake := range snapshot.Snakes() {
+		if snake.ID() == snapshot.You().ID() {
+			continue
+		}
+		fullCount *= len(snake.ForwardMoves())
+		if _, distant := distantMoves[snake.ID()]; !distant {
+			keptCount *= len(snake.ForwardMoves())
+		}
+	}
+	return fullCount, fullCount - keptCount
+}
+
 // for convenient debug printing of move combo collection
-func getMoveComboList(moveCombinations []map[string]rules.SnakeMove) [][]string {
+func getMoveComboList(moveCombinations []moveCombination) [][]string {
 	var result [][]string
 	for _, combo := range moveCombinations {
 		var moves []string
-		for _, snakeMove := range combo {
+		for _, snakeMove := range combo.moves {
 			moves = append(moves, snakeMove.Move)
 		}
 		result = append(result, moves)
@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/Battle-Bunker/cyphid-snake/lib"
+	"github.com/samber/lo"
+)
+
+// Aggregator combines the scores of every possible next state of a move (one per combination
+// of opponent replies) into a single score for that move. Weights are the probabilities of
+// each combination under the opponent model. The choice of aggregator sets how risk-averse
+// the agent is: the mean weighs replies by likelihood, the minimum assumes the worst.
+type Aggregator interface {
+	Name() string
+	Aggregate(scores []float64, weights []float64) float64
+}
+
+// AggregatorFunc combines scores of next states given their weights.
+type AggregatorFunc func(scores []float64, weights []float64) float64
+
+func NewAggregator(name string, f AggregatorFunc) Aggregator {
+	return aggregatorImpl{
+		name: name,
+		f:    f,
+	}
+}
+
+// MeanAggregator scores a move by the expected score over opponent replies.
+func MeanAggregator() Aggregator {
+	return NewAggregator("mean", lib.WeightedMean)
+}
+
+// MinAggregator scores a move by its worst possible opponent reply (paranoid search).
+func MinAggregator() Aggregator {
+	return NewAggregator("min", func(scores []float64, weights []float64) float64 {
+		return lo.Min(possibleScores(scores, weights))
+	})
+}
+
+// MaxAggregator scores a move by its best possible opponent reply.
+func MaxAggregator() Aggregator {
+	return NewAggregator("max", func(scores []float64, weights []float64) float64 {
+		return lo.Max(possibleScores(scores, weights))
+	})
+}
+
+// PercentileAggregator scores a move by the p-th percentile (0..100) of its opponent replies.
+func PercentileAggregator(p float64) Aggregator {
+	return NewAggregator(fmt.Sprintf("p%g", p), func(scores []float64, weights []float64) float64 {
+		return lib.WeightedPercentile(scores, weights, p)
+	})
+}
+
+// CVaRAggregator scores a move by the expected score of its worst alpha fraction (0..1] of opponent replies.
+func CVaRAggregator(alpha float64) Aggregator {
+	return NewAggregator(fmt.Sprintf("cvar%g", alpha), func(scores []float64, weights []float64) float64 {
+		return lib.WeightedConditionalValueAtRisk(scores, weights, alpha)
+	})
+}
+
+// possibleScores drops the scores of replies the opponent model rules out.
+func possibleScores(scores []float64, weights []float64) []float64 {
+	return lo.Filter(scores, func(_ float64, i int) bool {
+		return weights[i] > 0
+	})
+}
+
+type aggregatorImpl struct {
+	name string
+	f    AggregatorFunc
+}
+
+func (a aggregatorImpl) Name() string {
+	return a.name
+}
+
+func (a aggregatorImpl) Aggregate(scores []float64, weights []float64) float64 {
+	if len(scores) == 0 {
+		return 0
+	}
+	return a.f(scores, weights)
+}
@@ -0,0 +1,183 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/BattlesnakeOfficial/rules/client"
+)
+
+// Config describes a snake declaratively, so that variants can be shipped as JSON files instead of code.
+//
+//	{
+//	  "metadata": {"apiversion": "1", "color": "#FF7F7F"},
+//	  "temperature": {"schedule": "turn-decay", "initial": 10, "final": 1, "halfLife": 50, "adaptiveGapScale": 5},
+//	  "aggregator": {"mode": "cvar", "alpha": 0.25},
+//	  "portfolio": [
+//	    {"heuristic": "team-health", "weight": 1, "normalization": "min-max"},
+//	    {"heuristic": "trap", "weight": 2, "params": {"closingTurns": 4}}
+//	  ]
+//	}
+type Config struct {
+	Metadata client.SnakeMetadataResponse `json:"metadata"`
+	// Temperature sets the softmax temperature; a constant 5 if not set.
+	Temperature *TemperatureConfig `json:"temperature,omitempty"`
+	// Aggregator combines the scores of a move's possible next states; the mean if not set.
+	Aggregator *AggregatorConfig `json:"aggregator,omitempty"`
+	Portfolio  []HeuristicConfig `json:"portfolio"`
+}
+
+// HeuristicConfig is one heuristic of a configured portfolio.
+type HeuristicConfig struct {
+	// Heuristic is the ID the heuristic was registered under.
+	Heuristic string `json:"heuristic"`
+	// Name labels the heuristic in logs and decisions; the ID if not set.
+	Name   string         `json:"name,omitempty"`
+	Weight float64        `json:"weight"`
+	Params map[string]any `json:"params,omitempty"`
+	// Aggregator overrides the config's aggregator for this heuristic.
+	Aggregator *AggregatorConfig `json:"aggregator,omitempty"`
+	// Normalization is "none", "min-max", "z-score" or "rank"; none if not set.
+	Normalization string `json:"normalization,omitempty"`
+}
+
+// TemperatureConfig selects a TemperatureSchedule. Schedule is "constant" (Value), "turn-decay"
+// (Initial, Final, HalfLife) or "occupancy-decay" (Initial, Final). A positive AdaptiveGapScale
+// wraps the schedule in AdaptiveTemperature.
+type TemperatureConfig struct {
+	Schedule         string  `json:"schedule"`
+	Value            float64 `json:"value,omitempty"`
+	Initial          float64 `json:"initial,omitempty"`
+	Final            float64 `json:"final,omitempty"`
+	HalfLife         int     `json:"halfLife,omitempty"`
+	AdaptiveGapScale float64 `json:"adaptiveGapScale,omitempty"`
+}
+
+// AggregatorConfig selects an Aggregator. Mode is "mean", "min", "max", "percentile" (P, in [0, 100])
+// or "cvar" (Alpha, in (0, 1]).
+type AggregatorConfig struct {
+	Mode  string   `json:"mode"`
+	P     *float64 `json:"p,omitempty"`
+	Alpha *float64 `json:"alpha,omitempty"`
+}
+
+// LoadConfig reads a Config from a JSON file, rejecting fields it does not know.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return config, fmt.Errorf("config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// NewSnakeAgentFromConfig builds an agent from the config, with the registered heuristics it names.
+func NewSnakeAgentFromConfig(config Config) (*SnakeAgent, error) {
+	heuristics := make([]WeightedHeuristic, 0, len(config.Portfolio))
+	for _, heuristicConfig := range config.Portfolio {
+		heuristic, err := heuristicConfig.build()
+		if err != nil {
+			return nil, err
+		}
+		heuristics = append(heuristics, heuristic)
+	}
+
+	snakeAgent := NewSnakeAgent(NewPortfolio(heuristics...), config.Metadata)
+	if config.Temperature != nil {
+		schedule, err := config.Temperature.build()
+		if err != nil {
+			return nil, err
+		}
+		snakeAgent.TemperatureSchedule = schedule
+	}
+	if config.Aggregator != nil {
+		aggregator, err := config.Aggregator.build()
+		if err != nil {
+			return nil, err
+		}
+		snakeAgent.Aggregator = aggregator
+	}
+	return snakeAgent, nil
+}
+
+func (c HeuristicConfig) build() (WeightedHeuristic, error) {
+	name := c.Name
+	if name == "" {
+		name = c.Heuristic
+	}
+	heuristic, err := NewRegisteredHeuristic(c.Heuristic, c.Weight, name, c.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Aggregator != nil {
+		aggregator, err := c.Aggregator.build()
+		if err != nil {
+			return nil, fmt.Errorf("heuristic '%s': %w", name, err)
+		}
+		heuristic = WithAggregator(heuristic, aggregator)
+	}
+	if c.Normalization != "" {
+		normalization, err := ParseNormalization(c.Normalization)
+		if err != nil {
+			return nil, fmt.Errorf("heuristic '%s': %w", name, err)
+		}
+		heuristic = WithNormalization(heuristic, normalization)
+	}
+	return heuristic, nil
+}
+
+func (c TemperatureConfig) build() (TemperatureSchedule, error) {
+	var schedule TemperatureSchedule
+	switch c.Schedule {
+	case "constant":
+		schedule = ConstantTemperature(c.Value)
+	case "turn-decay":
+		schedule = TurnDecayTemperature(c.Initial, c.Final, c.HalfLife)
+	case "occupancy-decay":
+		schedule = OccupancyDecayTemperature(c.Initial, c.Final)
+	default:
+		return nil, fmt.Errorf("unknown temperature schedule '%s'", c.Schedule)
+	}
+	if c.AdaptiveGapScale > 0 {
+		schedule = AdaptiveTemperature(schedule, c.AdaptiveGapScale)
+	}
+	return schedule, nil
+}
+
+func (c AggregatorConfig) build() (Aggregator, error) {
+	switch c.Mode {
+	case "mean":
+		return MeanAggregator(), nil
+	case "min":
+		return MinAggregator(), nil
+	case "max":
+		return MaxAggregator(), nil
+	case "percentile":
+		if c.P == nil || *c.P < 0 || *c.P > 100 {
+			return nil, fmt.Errorf("percentile aggregator needs p in [0, 100], got %s", optionalFloat(c.P))
+		}
+		return PercentileAggregator(*c.P), nil
+	case "cvar":
+		if c.Alpha == nil || *c.Alpha <= 0 || *c.Alpha > 1 {
+			return nil, fmt.Errorf("cvar aggregator needs alpha in (0, 1], got %s", optionalFloat(c.Alpha))
+		}
+		return CVaRAggregator(*c.Alpha), nil
+	}
+	return nil, fmt.Errorf("unknown aggregator mode '%s'", c.Mode)
+}
+
+// optionalFloat formats a config value that may be missing.
+func optionalFloat(value *float64) string {
+	if value == nil {
+		return "none"
+	}
+	return fmt.Sprint(*value)
+}
@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"github.com/BattlesnakeOfficial/rules/client"
+	"github.com/samber/mo"
+)
+
+// MoveDecision records how the agent chose a move: the candidate moves, every score that went
+// into ranking them, and the random draw that picked one. Moves holds the sorted candidates that
+// survived the safety checks, and slices indexed per move are aligned with it.
+type MoveDecision struct {
+	Turn  int      `json:"turn"`
+	Moves []string `json:"moves"`
+	// Vetoes maps each forward move dropped by a safety check to the checks that vetoed it.
+	Vetoes map[string][]string `json:"vetoes,omitempty"`
+	// Depth is the deepest search that completed before the deadline.
+	Depth      int                  `json:"depth"`
+	Heuristics []HeuristicBreakdown `json:"heuristics"`
+	// Scores is the sum of the heuristics' weighted scores for each move, divided by the sum of the
+	// weights' magnitudes.
+	Scores []float64 `json:"scores"`
+	// Temperature is the softmax temperature the agent's schedule chose for this decision.
+	Temperature   float64   `json:"temperature"`
+	Probabilities []float64 `json:"probabilities"`
+	// Seed seeded the generator that drew Draw; see SnakeAgent.Replay.
+	Seed int64 `json:"seed"`
+	// Draw is the uniform random number in [0, 1) that sampled Move from Probabilities.
+	Draw float64 `json:"draw"`
+	// Plan maps each teammate's ID to its move when the decision came from the team planner.
+	Plan map[string]string `json:"plan,omitempty"`
+	Move string            `json:"move"`
+	// Intent is what we announce to teammates in the shout, if anything.
+	Intent mo.Option[Intent] `json:"intent"`
+}
+
+// HeuristicBreakdown is one heuristic's contribution to a MoveDecision, with scores aligned with its Moves.
+type HeuristicBreakdown struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+	// RawScores are the heuristic's scores aggregated over each move's next states, before weighting.
+	RawScores []float64 `json:"rawScores"`
+	// NormalizedScores are the raw scores after the heuristic's Normalization across the moves.
+	NormalizedScores []float64 `json:"normalizedScores"`
+	WeightedScores   []float64 `json:"weightedScores"`
+	// Parts splits the raw scores of a BreakdownHeuristic into its named parts. They are aggregated over
+	// each move's next states alone, without the deeper search, so need not add up to RawScores.
+	Parts map[string][]float64 `json:"parts,omitempty"`
+}
+
+// Response converts the decision to the move response sent back to the game engine,
+// shouting the encoded intent ahead of the usual text.
+func (d MoveDecision) Response() client.MoveResponse {
+	return client.MoveResponse{
+		Move:  d.Move,
+		Shout: shoutWithIntent(d.Intent, "I'm moving "+d.Move),
+	}
+}
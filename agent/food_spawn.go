@@ -0,0 +1,83 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+)
+
+// FoodSpawnMode sets how simulated turns spawn food. The engine places new food at random, so
+// simulating that faithfully makes two evaluations of the same move see different boards.
+type FoodSpawnMode int
+
+const (
+	// FoodSpawnNone never spawns food in simulated turns, so simulations are repeatable.
+	FoodSpawnNone FoodSpawnMode = iota
+	// FoodSpawnRandom spawns food as the engine does, drawing from the global generator.
+	FoodSpawnRandom
+	// FoodSpawnExpected spawns no food on the board, but keeps a running count of the food the engine
+	// would be expected to have spawned, from the game's MinimumFood and FoodSpawnChance; see ExpectedFood.
+	FoodSpawnExpected
+)
+
+func (m FoodSpawnMode) String() string {
+	switch m {
+	case FoodSpawnNone:
+		return "none"
+	case FoodSpawnRandom:
+		return "random"
+	case FoodSpawnExpected:
+		return "expected"
+	}
+	return fmt.Sprintf("FoodSpawnMode(%d)", int(m))
+}
+
+// WithFoodSpawn returns the snapshot with its simulated turns spawning food according to mode.
+func (g *gameSnapshotImpl) WithFoodSpawn(mode FoodSpawnMode) GameSnapshot {
+	snapshot := g.withBoardState(g.boardState)
+	snapshot.foodSpawn = mode
+	return snapshot
+}
+
+// ExpectedFood is the expected number of food items the engine would have spawned over the simulated
+// turns leading to this snapshot but which are not on the board, for heuristics to weigh as food that
+// could be anywhere. It is always zero unless the snapshot simulates with FoodSpawnExpected.
+func (g *gameSnapshotImpl) ExpectedFood() float64 {
+	return g.expectedFood
+}
+
+// mapSettings are the settings passed to the map's update steps, with food spawning switched off
+// unless the snapshot spawns food at random.
+func (g *gameSnapshotImpl) mapSettings() rules.Settings {
+	if g.foodSpawn == FoodSpawnRandom {
+		return g.ruleset.Settings()
+	}
+	return g.spawnlessSettings
+}
+
+// withoutFoodSpawning returns the ruleset params with the map's food spawning switched off.
+func withoutFoodSpawning(params map[string]string) rules.Settings {
+	return rules.NewSettings(lo.Assign(params, map[string]string{
+		rules.ParamMinimumFood:     "0",
+		rules.ParamFoodSpawnChance: "0",
+	}))
+}
+
+// expectedFoodAfter adds the food expected to spawn at the end of a turn that finished on boardState.
+// As in the engine, a board short of MinimumFood is topped up, and otherwise one food spawns
+// with probability FoodSpawnChance percent; food already expected counts towards the minimum.
+func (g *gameSnapshotImpl) expectedFoodAfter(boardState *rules.BoardState) float64 {
+	if g.foodSpawn != FoodSpawnExpected {
+		return 0
+	}
+	settings := g.ruleset.Settings()
+	minimumFood := float64(settings.Int(rules.ParamMinimumFood, 0))
+	foodSpawnChance := float64(settings.Int(rules.ParamFoodSpawnChance, 0))
+
+	food := float64(len(boardState.Food)) + g.expectedFood
+	if food < minimumFood {
+		return minimumFood - float64(len(boardState.Food))
+	}
+	return g.expectedFood + foodSpawnChance/100
+}
@@ -3,14 +3,18 @@ package agent
 import (
 	"github.com/BattlesnakeOfficial/rules"
 	"github.com/BattlesnakeOfficial/rules/client"
+	"github.com/BattlesnakeOfficial/rules/maps"
 	"github.com/samber/lo"
 	"github.com/samber/mo"
 	// "encoding/json"
+	"hash/fnv"
 	"log"
+	"time"
 )
 
 type GameSnapshot interface {
 	GameID() string
+	Timeout() time.Duration
 	Rules() rules.Ruleset
 	Turn() int
 	Height() int
@@ -22,19 +26,32 @@ type GameSnapshot interface {
 	Teammates() []SnakeSnapshot
 	YourTeam() []SnakeSnapshot
 	Opponents() []SnakeSnapshot
+	AllOpponents() []SnakeSnapshot
+	AllYourTeam() []SnakeSnapshot
 	AllSnakes() []SnakeSnapshot
 	DeadSnakes() []SnakeSnapshot
 	ApplyMoves(moves []rules.SnakeMove) (GameSnapshot, error)
+	TeammateIntents() map[string]Intent
+	ForSnake(id string) GameSnapshot
+	WithFoodSpawn(mode FoodSpawnMode) GameSnapshot
+	ExpectedFood() float64
+	Hash() uint64
 }
 
 type gameSnapshotImpl struct {
 	gameID      string
+	timeout     time.Duration
 	ruleset     rules.Ruleset
+	gameMap     maps.GameMap      // nil if the game's map is not known
 	boardState  *rules.BoardState // must not be nil
 	snakeStats  map[string]*snakeStatsImpl
 	yourID      string
 	allyIDs     []string
 	opponentIDs []string
+
+	foodSpawn         FoodSpawnMode
+	spawnlessSettings rules.Settings // the ruleset's settings with food spawning switched off
+	expectedFood      float64
 }
 
 // GameSnapshot interface implementation
@@ -43,6 +60,11 @@ func (g *gameSnapshotImpl) GameID() string {
 	return g.gameID
 }
 
+// Timeout is the time allowed to respond to a move request, or zero if the game did not specify one.
+func (g *gameSnapshotImpl) Timeout() time.Duration {
+	return g.timeout
+}
+
 func (g *gameSnapshotImpl) Turn() int {
 	return g.boardState.Turn
 }
@@ -137,6 +159,20 @@ func (g *gameSnapshotImpl) Opponents() []SnakeSnapshot {
 	})
 }
 
+// AllOpponents returns every snake outside our team, eliminated ones included.
+func (g *gameSnapshotImpl) AllOpponents() []SnakeSnapshot {
+	return lo.Map(g.opponentIDs, func(id string, _ int) SnakeSnapshot {
+		return g.getSnakeById(id)
+	})
+}
+
+// AllYourTeam returns every snake in our team, ourselves and eliminated ones included.
+func (g *gameSnapshotImpl) AllYourTeam() []SnakeSnapshot {
+	return lo.Map(g.allyIDs, func(id string, _ int) SnakeSnapshot {
+		return g.getSnakeById(id)
+	})
+}
+
 func (g *gameSnapshotImpl) ApplyMoves(moves []rules.SnakeMove) (GameSnapshot, error) {
 	if len(moves) == 0 {
 		log.Fatalf("No moves provided: %+v", moves)
@@ -156,7 +192,103 @@ func (g *gameSnapshotImpl) ApplyMoves(moves []rules.SnakeMove) (GameSnapshot, er
 		log.Printf("Error executing moves: %v", err)
 		return nil, err
 	}
-	return g.UpdateGameSnapshotBoardState(nextBoardState), nil
+
+	expectedFood := g.expectedFoodAfter(nextBoardState)
+	nextBoardState = g.advanceTurn(nextBoardState)
+	if g.keepsHazards() {
+		nextBoardState.Hazards = g.boardState.Hazards
+	}
+	nextSnapshot := g.withBoardState(nextBoardState)
+	nextSnapshot.expectedFood = expectedFood
+	return nextSnapshot, nil
+}
+
+// advanceTurn finishes a simulated turn the way the engine does: the map's post-update step runs on the
+// executed board, the turn advances, and the map's pre-update step prepares the board the snakes will see.
+func (g *gameSnapshotImpl) advanceTurn(boardState *rules.BoardState) *rules.BoardState {
+	if g.gameMap == nil {
+		boardState.Turn += 1
+		return boardState
+	}
+
+	settings := g.mapSettings()
+	updated, err := maps.PostUpdateBoard(g.gameMap, boardState, settings)
+	if err != nil {
+		log.Printf("Error updating map %s: %v", g.gameMap.ID(), err)
+		updated = boardState
+	}
+	updated.Turn += 1
+	prepared, err := maps.PreUpdateBoard(g.gameMap, updated, settings)
+	if err != nil {
+		log.Printf("Error preparing map %s: %v", g.gameMap.ID(), err)
+		return updated
+	}
+	return prepared
+}
+
+// keepsHazards reports whether simulated turns keep the board's hazards rather than update them. The royale
+// ruleset and the maps in randomHazardMaps place hazards at random from the engine's seed, which snakes are
+// never told, so guessing would put hazards where the engine won't.
+func (g *gameSnapshotImpl) keepsHazards() bool {
+	return g.ruleset.Name() == rules.GameTypeRoyale || (g.gameMap != nil && randomHazardMaps[g.gameMap.ID()])
+}
+
+// randomHazardMaps place their hazards at random as the game goes on.
+var randomHazardMaps = map[string]bool{
+	"royale":            true,
+	"healing_pools":     true,
+	"hz_hazard_pits":    true,
+	"hz_spiral":         true,
+	"hz_scatter":        true,
+	"hz_grow_box":       true,
+	"hz_expand_box":     true,
+	"hz_expand_scatter": true,
+}
+
+// mapSeed seeds the map steps from the game ID, so maps that draw random numbers, e.g. to place food,
+// update the same board the same way every time.
+func mapSeed(gameID string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(gameID))
+	return int64(h.Sum64()&^(1<<63)) | 1
+}
+
+// resolveGameMap looks up the request's map in the rules registry, treating a missing map as the standard one.
+// Maps that are unknown, or that reject the game's settings, are left out of the simulation.
+func resolveGameMap(mapID string, boardState *rules.BoardState, settings rules.Settings) maps.GameMap {
+	if mapID == "" {
+		mapID = "standard"
+	}
+	gameMap, err := maps.GetMap(mapID)
+	if err != nil {
+		log.Printf("Unknown map %q, simulating without map updates: %v", mapID, err)
+		return nil
+	}
+	if _, err := maps.PostUpdateBoard(gameMap, boardState, settings); err != nil {
+		log.Printf("Map %q cannot be simulated with these settings, simulating without map updates: %v", mapID, err)
+		return nil
+	}
+	return gameMap
+}
+
+// ForSnake returns the snapshot from the point of view of the snake with the given id:
+// You() is that snake, and its team is every snake sharing its color.
+func (g *gameSnapshotImpl) ForSnake(id string) GameSnapshot {
+	color := g.snakeStats[id].color
+
+	allyIDs := lo.FilterMap(g.boardState.Snakes, func(snake rules.Snake, _ int) (string, bool) {
+		return snake.ID, g.snakeStats[snake.ID].color == color
+	})
+
+	opponentIDs := lo.FilterMap(g.boardState.Snakes, func(snake rules.Snake, _ int) (string, bool) {
+		return snake.ID, g.snakeStats[snake.ID].color != color
+	})
+
+	snapshot := g.withBoardState(g.boardState)
+	snapshot.yourID = id
+	snapshot.allyIDs = allyIDs
+	snapshot.opponentIDs = opponentIDs
+	return snapshot
 }
 
 func NewGameSnapshot(request *client.SnakeRequest) GameSnapshot {
@@ -169,8 +301,9 @@ func NewGameSnapshot(request *client.SnakeRequest) GameSnapshot {
 	rulesetName := request.Game.Ruleset.Name
 	// log.Println("Creating game snapshot for ruleset:", rulesetName)
 
+	params := ConvertRulesetSettingsToMap(request.Game.Ruleset.Settings)
 	ruleset := rules.NewRulesetBuilder().
-		WithParams(ConvertRulesetSettingsToMap(request.Game.Ruleset.Settings)).
+		WithParams(params).
 		WithSolo(len(request.Board.Snakes) < 2).
 		NamedRuleset(rulesetName)
 
@@ -184,6 +317,7 @@ func NewGameSnapshot(request *client.SnakeRequest) GameSnapshot {
 
 		snakeStats[snake.ID] = &snakeStatsImpl{
 			name:            snake.Name,
+			color:           snake.Customizations.Color,
 			lastShout:       snake.Shout,
 			turnLastShouted: turnLastShouted,
 		}
@@ -200,12 +334,16 @@ func NewGameSnapshot(request *client.SnakeRequest) GameSnapshot {
 
 	return &gameSnapshotImpl{
 		gameID:      request.Game.ID,
+		timeout:     time.Duration(request.Game.Timeout) * time.Millisecond,
 		ruleset:     ruleset,
+		gameMap:     resolveGameMap(request.Game.Map, boardState, ruleset.Settings().WithSeed(mapSeed(request.Game.ID))),
 		boardState:  boardState,
 		snakeStats:  snakeStats,
 		yourID:      request.You.ID,
 		allyIDs:     allyIDs,
 		opponentIDs: opponentIDs,
+
+		spawnlessSettings: withoutFoodSpawning(params).WithSeed(mapSeed(request.Game.ID)),
 	}
 }
 
@@ -213,13 +351,12 @@ func (g *gameSnapshotImpl) UpdateGameSnapshotBoardState(newBoardState *rules.Boa
 	if newBoardState == nil {
 		panic("UpdateGameSnapshotBoardState: newBoardState is nil")
 	}
-	return &gameSnapshotImpl{
-		gameID:      g.gameID,
-		boardState:  newBoardState,
-		ruleset:     g.ruleset,
-		snakeStats:  g.snakeStats,
-		yourID:      g.yourID,
-		allyIDs:     g.allyIDs,
-		opponentIDs: g.opponentIDs,
-	}
+	return g.withBoardState(newBoardState)
+}
+
+// withBoardState copies the snapshot onto another board, keeping everything else about the game.
+func (g *gameSnapshotImpl) withBoardState(boardState *rules.BoardState) *gameSnapshotImpl {
+	snapshot := *g
+	snapshot.boardState = boardState
+	return &snapshot
 }
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/BattlesnakeOfficial/rules"
+)
+
+func TestApplyMovesRepeatableOnHazardMaps(t *testing.T) {
+	edge := []rules.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}}
+	tests := []struct {
+		gameMap string
+		ruleset string
+		turn    int // the turn before the map's hazard step fires
+		hazards []rules.Point
+	}{
+		{"royale", rules.GameTypeRoyale, 19, edge},
+		{"hz_spiral", rules.GameTypeStandard, 2, nil},
+		{"hz_scatter", rules.GameTypeStandard, 3, edge[:1]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.gameMap, func(t *testing.T) {
+			request := testRequest(tt.turn, []rules.Point{{X: 5, Y: 5}},
+				testSnake{id: "you", color: "#000000", health: 90, body: []rules.Point{{X: 3, Y: 3}, {X: 3, Y: 2}, {X: 3, Y: 1}}},
+				testSnake{id: "them", color: "#FFFFFF", health: 80, body: []rules.Point{{X: 6, Y: 4}, {X: 7, Y: 4}, {X: 8, Y: 4}}},
+			)
+			request.Game.Map = tt.gameMap
+			request.Game.Ruleset.Name = tt.ruleset
+			request.Game.Ruleset.Settings.HazardDamagePerTurn = 14
+			request.Game.Ruleset.Settings.RoyaleSettings.ShrinkEveryNTurns = 20
+			request.Board.Hazards = pointsToCoords(tt.hazards)
+			snapshot := NewGameSnapshot(request)
+			moves := []rules.SnakeMove{{ID: "you", Move: rules.MoveUp}, {ID: "them", Move: rules.MoveDown}}
+
+			first, err := snapshot.ApplyMoves(moves)
+			if err != nil {
+				t.Fatalf("ApplyMoves: %v", err)
+			}
+			if !reflect.DeepEqual(first.Hazards(), snapshot.Hazards()) {
+				t.Errorf("hazards = %v, want the board's %v", first.Hazards(), snapshot.Hazards())
+			}
+			for i := 0; i < 10; i++ {
+				next, err := snapshot.ApplyMoves(moves)
+				if err != nil {
+					t.Fatalf("ApplyMoves: %v", err)
+				}
+				if next.Hash() != first.Hash() {
+					t.Fatalf("application %d hashed %x, want %x", i+2, next.Hash(), first.Hash())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"github.com/Battle-Bunker/cyphid-snake/lib/grid"
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+)
+
+// BoardGrid returns the shape of the snapshot's board, wrapped if the ruleset wraps.
+func BoardGrid(snapshot GameSnapshot) grid.Grid {
+	return grid.New(snapshot.Width(), snapshot.Height(), isWrapped(snapshot))
+}
+
+// BoardOccupancy blocks every alive snake's body for as long as it will stay there: the segment
+// i places from the head of a snake of length n moves on after n-i turns, and a stacked tail
+// stays for as long as the segment nearest the head.
+func BoardOccupancy(snapshot GameSnapshot) *grid.Occupancy {
+	return BoardOccupancyWithout(snapshot)
+}
+
+// BoardOccupancyWithout is BoardOccupancy leaving the bodies of the snakes with the given IDs open.
+func BoardOccupancyWithout(snapshot GameSnapshot, ids ...string) *grid.Occupancy {
+	occupancy := grid.NewOccupancy(BoardGrid(snapshot))
+	for _, snake := range snapshot.Snakes() {
+		if lo.Contains(ids, snake.ID()) {
+			continue
+		}
+		body := snake.Body()
+		for i, p := range body {
+			occupancy.BlockFor(p, len(body)-i)
+		}
+	}
+	return occupancy
+}
+
+// HazardCost is the health a move onto each cell costs: 1 for the move, plus the game's hazard damage
+// for every hazard stacked on the cell. As in the rules, a snake eating on a hazard takes no damage.
+func HazardCost(snapshot GameSnapshot) grid.CostFunc {
+	damage := snapshot.Rules().Settings().Int(rules.ParamHazardDamagePerTurn, 0)
+	hazards := lo.CountValues(snapshot.Hazards())
+	food := lo.SliceToMap(snapshot.Food(), func(p rules.Point) (rules.Point, bool) { return p, true })
+	return func(p rules.Point) float64 {
+		if food[p] {
+			return 1
+		}
+		return float64(1 + damage*hazards[p])
+	}
+}
@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+	"github.com/samber/mo"
+)
+
+// Intents are shouted as "#i<turn>:<move>[:<x>,<y>]", e.g. "#i12:u:5,5", ahead of any human-readable text.
+// Turn is the turn the move will be made on, move is the first letter of the direction, and the optional
+// point is the food the snake is heading for. Shouts are only seen on the next turn, so a snake always
+// announces its move for the turn after the one it is answering.
+const (
+	intentPrefix   = "#i"
+	maxShoutLength = 256
+)
+
+// defaultIntentConfidence is both how far we trust teammates' announced moves and how firmly we keep our own.
+const defaultIntentConfidence = 0.9
+
+// Intent is a snake's announced plan: the move it will make on Turn, and the food it is heading for.
+type Intent struct {
+	Turn int                    `json:"turn"`
+	Move string                 `json:"move"`
+	Food mo.Option[rules.Point] `json:"food"`
+}
+
+// EncodeIntent returns the compact shout form of an intent.
+func EncodeIntent(intent Intent) string {
+	encoded := fmt.Sprintf("%s%d:%s", intentPrefix, intent.Turn, intent.Move[:1])
+	if food, ok := intent.Food.Get(); ok {
+		encoded += fmt.Sprintf(":%d,%d", food.X, food.Y)
+	}
+	return encoded
+}
+
+// DecodeIntent parses an intent from the start of a shout, reporting whether the shout carried one.
+func DecodeIntent(shout string) (Intent, bool) {
+	if !strings.HasPrefix(shout, intentPrefix) {
+		return Intent{}, false
+	}
+	fields := strings.Split(strings.Fields(shout)[0][len(intentPrefix):], ":")
+	if len(fields) < 2 {
+		return Intent{}, false
+	}
+
+	turn, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return Intent{}, false
+	}
+	move, found := lo.Find([]string{rules.MoveUp, rules.MoveDown, rules.MoveLeft, rules.MoveRight}, func(m string) bool {
+		return m[:1] == fields[1]
+	})
+	if !found {
+		return Intent{}, false
+	}
+
+	intent := Intent{Turn: turn, Move: move}
+	if len(fields) > 2 {
+		var food rules.Point
+		if _, err := fmt.Sscanf(fields[2], "%d,%d", &food.X, &food.Y); err == nil {
+			intent.Food = mo.Some(food)
+		}
+	}
+	return intent, true
+}
+
+// shoutWithIntent prefixes the shout text with the encoded intent, keeping within the shout length limit.
+func shoutWithIntent(intent mo.Option[Intent], text string) string {
+	if i, ok := intent.Get(); ok {
+		text = EncodeIntent(i) + " " + text
+	}
+	if len(text) > maxShoutLength {
+		text = text[:maxShoutLength]
+	}
+	return text
+}
+
+// TeammateIntents returns the intents teammates announced for this snapshot's turn, keyed by snake ID.
+func (g *gameSnapshotImpl) TeammateIntents() map[string]Intent {
+	intents := make(map[string]Intent)
+	for _, teammate := range g.Teammates() {
+		if intent, ok := AnnouncedIntent(g, teammate); ok {
+			intents[teammate.ID()] = intent
+		}
+	}
+	return intents
+}
+
+// AnnouncedIntent returns the intent the snake announced on the previous turn for the snapshot's turn, if any.
+func AnnouncedIntent(snapshot GameSnapshot, snake SnakeSnapshot) (Intent, bool) {
+	intent, ok := DecodeIntent(snake.LastShout())
+	return intent, ok && intent.Turn == snapshot.Turn()
+}
+
+// IntentAwareOpponentModel trusts teammates' announced moves: an announced move gets the given
+// confidence, and the rest of the probability is shared out according to the base model.
+// Snakes without an announcement for this turn follow the base model.
+func IntentAwareOpponentModel(base OpponentModel, confidence float64) OpponentModel {
+	return NewOpponentModel(fmt.Sprintf("intent-aware %s", base.Name()), func(snapshot GameSnapshot, snake SnakeSnapshot) []float64 {
+		probs := base.MoveProbabilities(snapshot, snake)
+		intent, announced := snapshot.TeammateIntents()[snake.ID()]
+		if !announced {
+			return probs
+		}
+		_, index, found := lo.FindIndexOf(snake.ForwardMoves(), func(move rules.SnakeMove) bool { return move.Move == intent.Move })
+		if !found {
+			return probs
+		}
+
+		othersTotal := lo.Sum(probs) - probs[index]
+		return lo.Map(probs, func(p float64, i int) float64 {
+			switch {
+			case i == index:
+				return confidence
+			case othersTotal > 0:
+				return (1 - confidence) * p / othersTotal
+			}
+			return 0
+		})
+	})
+}
+
+// intentAfter works out what we will announce after playing the move that leads to nextStates: the move
+// we expect to make on the following turn from the most likely of them, and the food nearest our new head.
+// The expected move is the one the search already found from that state when it got more than one ply
+// deep; otherwise it comes from a one-ply search, which is given up when ctx is done.
+func (sa *SnakeAgent) intentAfter(ctx context.Context, table *transpositionTable, snapshot GameSnapshot, nextStates []nextState, depth int) mo.Option[Intent] {
+	if len(nextStates) == 0 {
+		return mo.None[Intent]()
+	}
+	likeliest := lo.MaxBy(nextStates, func(a, b nextState) bool { return a.probability > b.probability }).snapshot
+	if !likeliest.You().Alive() {
+		return mo.None[Intent]()
+	}
+
+	nextMove, found := table.deepestBestMove(likeliest.Hash(), depth-1)
+	if !found {
+		var err error
+		if nextMove, _, err = sa.bestMove(ctx, table, likeliest, 1); err != nil || nextMove == "" {
+			log.Printf("No intent to announce for turn %d: %v", snapshot.Turn()+1, err)
+			return mo.None[Intent]()
+		}
+	}
+
+	intent := Intent{Turn: snapshot.Turn() + 1, Move: nextMove}
+	head := likeliest.You().Head()
+	if len(likeliest.Food()) > 0 {
+		intent.Food = mo.Some(lo.MinBy(likeliest.Food(), func(a, b rules.Point) bool {
+			return manhattanDistance(likeliest, head, a) < manhattanDistance(likeliest, head, b)
+		}))
+	}
+	return mo.Some(intent)
+}
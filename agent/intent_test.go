@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/Battle-Bunker/cyphid-snake/lib"
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/BattlesnakeOfficial/rules/client"
+	"github.com/samber/lo"
+)
+
+func TestIntentOnlyWithTeammates(t *testing.T) {
+	you := testSnake{id: "you", color: "#000000", health: 90, body: []rules.Point{{X: 3, Y: 3}, {X: 3, Y: 2}, {X: 3, Y: 1}}}
+	opponent := testSnake{id: "them", color: "#FFFFFF", health: 80, body: []rules.Point{{X: 8, Y: 8}, {X: 8, Y: 9}, {X: 9, Y: 9}}}
+	ally := testSnake{id: "ally", color: "#000000", health: 80, body: []rules.Point{{X: 8, Y: 2}, {X: 8, Y: 1}, {X: 9, Y: 1}}}
+
+	tests := []struct {
+		name        string
+		snakes      []testSnake
+		wantIntent  bool
+		wantCommits bool
+	}{
+		{"duel", []testSnake{you, opponent}, false, false},
+		{"squad", []testSnake{you, ally, opponent}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := testRequest(3, []rules.Point{{X: 5, Y: 5}}, tt.snakes...)
+			request.Board.Snakes[0].Shout = "#i3:l"
+			request.You = request.Board.Snakes[0]
+
+			snakeAgent := NewSnakeAgent(testPortfolio(), client.SnakeMetadataResponse{})
+			snakeAgent.PlanAsTeam = false
+			decision := snakeAgent.DecideWithSeed(NewGameSnapshot(request), 1)
+
+			if decision.Intent.IsPresent() != tt.wantIntent {
+				t.Errorf("intent = %v, want present %v", decision.Intent, tt.wantIntent)
+			}
+			left := lo.IndexOf(decision.Moves, rules.MoveLeft)
+			expected := lib.SoftmaxWithTemp(decision.Scores, decision.Temperature)[left]
+			if commits := decision.Probabilities[left] > expected+1e-9; commits != tt.wantCommits {
+				t.Errorf("p(left) = %.3f against a softmax of %.3f, want committed %v", decision.Probabilities[left], expected, tt.wantCommits)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"strings"
+
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+)
+
+// isWrapped reports whether the snapshot's ruleset wraps snakes around the board edges.
+func isWrapped(snapshot GameSnapshot) bool {
+	return strings.HasPrefix(snapshot.Rules().Name(), rules.GameTypeWrapped)
+}
+
+// movePoint returns the point reached by moving from p in the given direction,
+// wrapping around the board edges when the ruleset does.
+func movePoint(snapshot GameSnapshot, p rules.Point, move string) rules.Point {
+	return BoardGrid(snapshot).Move(p, move)
+}
+
+func inBounds(snapshot GameSnapshot, p rules.Point) bool {
+	return BoardGrid(snapshot).Contains(p)
+}
+
+// blockedNextTurn returns the body cells that will still be occupied after every snake moves once.
+// A tail cell frees up as its snake moves, unless the snake has just eaten and its tail is stacked.
+func blockedNextTurn(snapshot GameSnapshot) map[rules.Point]bool {
+	blocked := make(map[rules.Point]bool)
+	for _, snake := range snapshot.Snakes() {
+		body := snake.Body()
+		tailStacked := len(body) > 1 && body[len(body)-1] == body[len(body)-2]
+		for i, p := range body {
+			if i == len(body)-1 && !tailStacked {
+				continue
+			}
+			blocked[p] = true
+		}
+	}
+	return blocked
+}
+
+// isSafeMove reports whether a move keeps the snake on the board and clear of every snake's body.
+func isSafeMove(snapshot GameSnapshot, blocked map[rules.Point]bool, snake SnakeSnapshot, move string) bool {
+	next := movePoint(snapshot, snake.Head(), move)
+	return inBounds(snapshot, next) && !blocked[next]
+}
+
+// safeForwardMoves returns the snake's forward moves that pass isSafeMove.
+func safeForwardMoves(snapshot GameSnapshot, blocked map[rules.Point]bool, snake SnakeSnapshot) []rules.SnakeMove {
+	return lo.Filter(snake.ForwardMoves(), func(move rules.SnakeMove, _ int) bool {
+		return isSafeMove(snapshot, blocked, snake, move.Move)
+	})
+}
+
+// manhattanDistance returns the number of moves between two points, measured around the board edges
+// when the ruleset wraps.
+func manhattanDistance(snapshot GameSnapshot, a, b rules.Point) int {
+	return BoardGrid(snapshot).Distance(a, b)
+}
+
+// mostLikelySafeMove returns the snake's safe forward move with the highest probability under the
+// opponent model, or its most likely forward move when none is safe.
+func mostLikelySafeMove(snapshot GameSnapshot, blocked map[rules.Point]bool, snake SnakeSnapshot, model OpponentModel) rules.SnakeMove {
+	forwardMoves := snake.ForwardMoves()
+	probs := model.MoveProbabilities(snapshot, snake)
+	safe := lo.Map(forwardMoves, func(move rules.SnakeMove, _ int) bool {
+		return isSafeMove(snapshot, blocked, snake, move.Move)
+	})
+	anySafe := lo.Contains(safe, true)
+
+	best := -1
+	for i := range forwardMoves {
+		if anySafe && !safe[i] {
+			continue
+		}
+		if best < 0 || probs[i] > probs[best] {
+			best = i
+		}
+	}
+	return forwardMoves[best]
+}
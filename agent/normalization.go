@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/samber/lo"
+)
+
+// Normalization rescales one heuristic's scores across the candidate moves before they are weighted,
+// so that heuristics on different scales can be traded off by their weights alone.
+type Normalization int
+
+const (
+	// NormalizeNone leaves the scores as they are.
+	NormalizeNone Normalization = iota
+	// NormalizeMinMax maps the worst move to 0 and the best to 1.
+	NormalizeMinMax
+	// NormalizeZScore subtracts the mean and divides by the standard deviation.
+	NormalizeZScore
+	// NormalizeRank replaces each score by its rank from 0 for the worst move to 1 for the best,
+	// sharing the average rank between ties.
+	NormalizeRank
+)
+
+func (n Normalization) String() string {
+	switch n {
+	case NormalizeNone:
+		return "none"
+	case NormalizeMinMax:
+		return "min-max"
+	case NormalizeZScore:
+		return "z-score"
+	case NormalizeRank:
+		return "rank"
+	}
+	return fmt.Sprintf("Normalization(%d)", int(n))
+}
+
+// ParseNormalization returns the normalization with the given name, as returned by String.
+func ParseNormalization(name string) (Normalization, error) {
+	for _, n := range []Normalization{NormalizeNone, NormalizeMinMax, NormalizeZScore, NormalizeRank} {
+		if n.String() == name {
+			return n, nil
+		}
+	}
+	return NormalizeNone, fmt.Errorf("unknown normalization '%s'", name)
+}
+
+// Apply normalizes scores aligned with the candidate moves. Moves that score the same stay level.
+func (n Normalization) Apply(scores []float64) []float64 {
+	switch n {
+	case NormalizeMinMax:
+		low, high := lo.Min(scores), lo.Max(scores)
+		return lo.Map(scores, func(score float64, _ int) float64 {
+			if high == low {
+				return 0
+			}
+			return (score - low) / (high - low)
+		})
+	case NormalizeZScore:
+		mean := lo.Sum(scores) / float64(max(len(scores), 1))
+		variance := lo.SumBy(scores, func(score float64) float64 { return (score - mean) * (score - mean) }) / float64(max(len(scores), 1))
+		return lo.Map(scores, func(score float64, _ int) float64 {
+			if variance == 0 {
+				return 0
+			}
+			return (score - mean) / math.Sqrt(variance)
+		})
+	case NormalizeRank:
+		sorted := slices.Clone(scores)
+		slices.Sort(sorted)
+		return lo.Map(scores, func(score float64, _ int) float64 {
+			if len(scores) < 2 {
+				return 0
+			}
+			first, _ := slices.BinarySearch(sorted, score)
+			last := first
+			for last+1 < len(sorted) && sorted[last+1] == score {
+				last++
+			}
+			return float64(first+last) / 2 / float64(len(scores)-1)
+		})
+	}
+	return slices.Clone(scores)
+}
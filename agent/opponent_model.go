@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Battle-Bunker/cyphid-snake/lib"
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+)
+
+// OpponentModel predicts how the other snakes on the board move. MoveProbabilities returns a
+// probability for each of the snake's forward moves, aligned with snake.ForwardMoves().
+type OpponentModel interface {
+	Name() string
+	MoveProbabilities(snapshot GameSnapshot, snake SnakeSnapshot) []float64
+}
+
+// OpponentModelFunc returns unnormalized move weights aligned with snake.ForwardMoves().
+type OpponentModelFunc func(snapshot GameSnapshot, snake SnakeSnapshot) []float64
+
+func NewOpponentModel(name string, f OpponentModelFunc) OpponentModel {
+	return opponentModelImpl{
+		name: name,
+		f:    f,
+	}
+}
+
+// UniformOpponentModel gives every forward move the same probability.
+func UniformOpponentModel() OpponentModel {
+	return NewOpponentModel("uniform", func(_ GameSnapshot, snake SnakeSnapshot) []float64 {
+		return lo.Map(snake.ForwardMoves(), func(_ rules.SnakeMove, _ int) float64 { return 1 })
+	})
+}
+
+// SafeMoveOpponentModel assumes snakes never drive into a wall or a body while a safe move exists,
+// and picks uniformly among their safe moves.
+func SafeMoveOpponentModel() OpponentModel {
+	return NewOpponentModel("safe", func(snapshot GameSnapshot, snake SnakeSnapshot) []float64 {
+		blocked := blockedNextTurn(snapshot)
+		return lo.Map(snake.ForwardMoves(), func(move rules.SnakeMove, _ int) float64 {
+			return lo.Ternary(isSafeMove(snapshot, blocked, snake, move.Move), 1.0, 0.0)
+		})
+	})
+}
+
+// PortfolioOpponentModel scores each of a snake's moves with the given portfolio, from that snake's
+// point of view, and turns the scores into probabilities with a softmax at the given temperature.
+// The other snakes are assumed to play their first safe move.
+func PortfolioOpponentModel(portfolio HeuristicPortfolio, temperature float64) OpponentModel {
+	return NewOpponentModel(fmt.Sprintf("portfolio, t=%.2f", temperature), func(snapshot GameSnapshot, snake SnakeSnapshot) []float64 {
+		blocked := blockedNextTurn(snapshot)
+		otherMoves := lo.FilterMap(snapshot.Snakes(), func(other SnakeSnapshot, _ int) (rules.SnakeMove, bool) {
+			moves := append(safeForwardMoves(snapshot, blocked, other), other.ForwardMoves()...)
+			return moves[0], other.ID() != snake.ID()
+		})
+
+		scores := make([]float64, 0, 4)
+		for _, move := range snake.ForwardMoves() {
+			nextState, err := snapshot.ApplyMoves(append([]rules.SnakeMove{move}, otherMoves...))
+			if err != nil {
+				log.Printf("Opponent model for %s could not apply moves: %v", snake.Name(), err)
+				return nil
+			}
+			scores = append(scores, portfolio.WeightedScore(portfolio.Scores(nextState.ForSnake(snake.ID()))))
+		}
+		return lib.SoftmaxWithTemp(scores, temperature)
+	})
+}
+
+type opponentModelImpl struct {
+	name string
+	f    OpponentModelFunc
+}
+
+func (m opponentModelImpl) Name() string {
+	return m.name
+}
+
+// MoveProbabilities normalizes the model's weights, falling back to uniform when the model
+// rules out every move.
+func (m opponentModelImpl) MoveProbabilities(snapshot GameSnapshot, snake SnakeSnapshot) []float64 {
+	numMoves := len(snake.ForwardMoves())
+	weights := m.f(snapshot, snake)
+	total := lo.Sum(weights)
+	if len(weights) != numMoves || total <= 0 {
+		return lo.Map(lo.Range(numMoves), func(_ int, _ int) float64 { return 1 / float64(numMoves) })
+	}
+	return lo.Map(weights, func(w float64, _ int) float64 { return w / total })
+}
@@ -2,6 +2,9 @@ package agent
 
 import (
 	"fmt"
+	"math"
+
+	"github.com/samber/lo"
 )
 
 // HeuristicPortfolio represents a collection of weighted heuristics.
@@ -12,6 +15,10 @@ type WeightedHeuristic interface {
 	Name() string
 	F() HeuristicFunc
 	Weight() float64
+	// Aggregator overrides the agent's aggregation of opponent replies for this heuristic, or is nil.
+	Aggregator() Aggregator
+	// Normalization rescales the heuristic's scores across candidate moves before weighting.
+	Normalization() Normalization
 	NameAndWeight() string
 }
 
@@ -19,10 +26,58 @@ type WeightedHeuristic interface {
 // It takes a GameSnapshot as input and returns a float64 score.
 type HeuristicFunc func(GameSnapshot) float64
 
+// BreakdownFunc scores a snapshot in named parts, such as one part per opponent.
+type BreakdownFunc func(GameSnapshot) map[string]float64
+
+// BreakdownHeuristic is a heuristic whose score is the sum of named parts, which MoveDecision reports separately.
+type BreakdownHeuristic interface {
+	WeightedHeuristic
+	Breakdown(snapshot GameSnapshot) map[string]float64
+}
+
 func NewPortfolio(heuristics ...WeightedHeuristic) HeuristicPortfolio {
 	return HeuristicPortfolio(heuristics)
 }
 
+// Scores evaluates every heuristic on the snapshot, returning raw scores aligned with the portfolio.
+func (p HeuristicPortfolio) Scores(snapshot GameSnapshot) []float64 {
+	return lo.Map(p, func(heuristic WeightedHeuristic, _ int) float64 {
+		return heuristic.F()(snapshot)
+	})
+}
+
+// weightScale is what weighted scores are divided by: the sum of the weights' magnitudes, so that
+// portfolios with negative weights keep their sign and an all-zero portfolio does not divide by zero.
+func (p HeuristicPortfolio) weightScale() float64 {
+	scale := lo.SumBy(p, func(heuristic WeightedHeuristic) float64 {
+		return math.Abs(heuristic.Weight())
+	})
+	if scale == 0 {
+		return 1
+	}
+	return scale
+}
+
+// WeightedScore combines raw scores aligned with the portfolio into a single weight-normalized score,
+// without any normalization across moves.
+func (p HeuristicPortfolio) WeightedScore(scores []float64) float64 {
+	scale := p.weightScale()
+	return lo.Sum(lo.Map(p, func(heuristic WeightedHeuristic, i int) float64 {
+		return scores[i] * heuristic.Weight() / scale
+	}))
+}
+
+// MoveScores combines the raw scores of each candidate move, aligned with the portfolio, into one
+// weight-normalized score per move, after normalizing each heuristic's scores across the moves.
+func (p HeuristicPortfolio) MoveScores(moveScores [][]float64) []float64 {
+	normalized := lo.Map(p, func(heuristic WeightedHeuristic, i int) []float64 {
+		return heuristic.Normalization().Apply(lo.Map(moveScores, func(scores []float64, _ int) float64 { return scores[i] }))
+	})
+	return lo.Map(moveScores, func(_ []float64, m int) float64 {
+		return p.WeightedScore(lo.Map(normalized, func(scores []float64, _ int) float64 { return scores[m] }))
+	})
+}
+
 func NewHeuristic(weight float64, name string, f HeuristicFunc) WeightedHeuristic {
 	return weightedHeuristicImpl{
 		name:   name,
@@ -31,11 +86,62 @@ func NewHeuristic(weight float64, name string, f HeuristicFunc) WeightedHeuristi
 	}
 }
 
+func NewHeuristicWithAggregator(weight float64, name string, f HeuristicFunc, aggregator Aggregator) WeightedHeuristic {
+	return weightedHeuristicImpl{
+		name:       name,
+		f:          f,
+		weight:     weight,
+		aggregator: aggregator,
+	}
+}
+
+// NewHeuristicWithBreakdown returns a heuristic scoring the sum of the parts from f.
+func NewHeuristicWithBreakdown(weight float64, name string, f BreakdownFunc) WeightedHeuristic {
+	return breakdownHeuristicImpl{
+		weightedHeuristicImpl: weightedHeuristicImpl{
+			name: name,
+			f: func(snapshot GameSnapshot) float64 {
+				return lo.Sum(lo.Values(f(snapshot)))
+			},
+			weight: weight,
+		},
+		breakdown: f,
+	}
+}
+
+// WithAggregator returns the heuristic with its next-state scores combined by aggregator.
+func WithAggregator(heuristic WeightedHeuristic, aggregator Aggregator) WeightedHeuristic {
+	switch h := heuristic.(type) {
+	case weightedHeuristicImpl:
+		h.aggregator = aggregator
+		return h
+	case breakdownHeuristicImpl:
+		h.aggregator = aggregator
+		return h
+	}
+	panic(fmt.Sprintf("WithAggregator: unsupported heuristic %T", heuristic))
+}
+
+// WithNormalization returns the heuristic with its scores normalized across candidate moves by normalization.
+func WithNormalization(heuristic WeightedHeuristic, normalization Normalization) WeightedHeuristic {
+	switch h := heuristic.(type) {
+	case weightedHeuristicImpl:
+		h.normalization = normalization
+		return h
+	case breakdownHeuristicImpl:
+		h.normalization = normalization
+		return h
+	}
+	panic(fmt.Sprintf("WithNormalization: unsupported heuristic %T", heuristic))
+}
+
 // weightedHeuristicImpl represents a heuristic with an associated weight and name.
 type weightedHeuristicImpl struct {
-	name   string
-	f      HeuristicFunc
-	weight float64
+	name          string
+	f             HeuristicFunc
+	weight        float64
+	aggregator    Aggregator
+	normalization Normalization
 }
 
 func (w weightedHeuristicImpl) Name() string {
@@ -50,6 +156,30 @@ func (w weightedHeuristicImpl) Weight() float64 {
 	return w.weight
 }
 
+func (w weightedHeuristicImpl) Aggregator() Aggregator {
+	return w.aggregator
+}
+
+func (w weightedHeuristicImpl) Normalization() Normalization {
+	return w.normalization
+}
+
 func (w weightedHeuristicImpl) NameAndWeight() string {
-	return fmt.Sprintf("%s, w=%.2f", w.name, w.weight)
+	nameAndWeight := fmt.Sprintf("%s, w=%.2f", w.name, w.weight)
+	if w.aggregator != nil {
+		nameAndWeight += ", " + w.aggregator.Name()
+	}
+	if w.normalization != NormalizeNone {
+		nameAndWeight += ", " + w.normalization.String()
+	}
+	return nameAndWeight
+}
+
+type breakdownHeuristicImpl struct {
+	weightedHeuristicImpl
+	breakdown BreakdownFunc
+}
+
+func (b breakdownHeuristicImpl) Breakdown(snapshot GameSnapshot) map[string]float64 {
+	return b.breakdown(snapshot)
 }
@@ -0,0 +1,194 @@
+package agent
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/samber/lo"
+)
+
+// ParamKind is the type of a heuristic parameter.
+type ParamKind int
+
+const (
+	IntParam ParamKind = iota
+	FloatParam
+	BoolParam
+	StringParam
+)
+
+func (k ParamKind) String() string {
+	switch k {
+	case IntParam:
+		return "int"
+	case FloatParam:
+		return "float"
+	case BoolParam:
+		return "bool"
+	case StringParam:
+		return "string"
+	}
+	return fmt.Sprintf("ParamKind(%d)", int(k))
+}
+
+// ParamSpec declares one parameter a registered heuristic accepts, with its value when none is given.
+type ParamSpec struct {
+	Name    string
+	Kind    ParamKind
+	Default any
+}
+
+// HeuristicParams holds the checked parameter values a heuristic is built with. Every declared
+// parameter is present, with its default if it was not given.
+type HeuristicParams struct {
+	values map[string]any
+}
+
+// Int returns an IntParam's value.
+func (p HeuristicParams) Int(name string) int {
+	return p.values[name].(int)
+}
+
+// Float returns a FloatParam's value.
+func (p HeuristicParams) Float(name string) float64 {
+	return p.values[name].(float64)
+}
+
+// Bool returns a BoolParam's value.
+func (p HeuristicParams) Bool(name string) bool {
+	return p.values[name].(bool)
+}
+
+// String returns a StringParam's value.
+func (p HeuristicParams) String(name string) string {
+	return p.values[name].(string)
+}
+
+// HeuristicFactory builds a registered heuristic's scoring function from its parameters.
+type HeuristicFactory func(params HeuristicParams) HeuristicFunc
+
+// BreakdownFactory builds a registered heuristic's scoring function in parts from its parameters.
+type BreakdownFactory func(params HeuristicParams) BreakdownFunc
+
+type registeredHeuristic struct {
+	params    []ParamSpec
+	factory   HeuristicFactory
+	breakdown BreakdownFactory
+}
+
+var heuristicRegistry = struct {
+	sync.RWMutex
+	heuristics map[string]registeredHeuristic
+}{heuristics: make(map[string]registeredHeuristic)}
+
+// RegisterHeuristic makes a heuristic available to portfolio configs under id, accepting the given
+// parameters. It is meant to be called from init, and panics if id is already registered.
+func RegisterHeuristic(id string, params []ParamSpec, factory HeuristicFactory) {
+	register(id, registeredHeuristic{params: params, factory: factory})
+}
+
+// RegisterHeuristicFunc registers a heuristic that takes no parameters.
+func RegisterHeuristicFunc(id string, f HeuristicFunc) {
+	RegisterHeuristic(id, nil, func(HeuristicParams) HeuristicFunc { return f })
+}
+
+// RegisterBreakdownHeuristic registers a heuristic scored in parts; see NewHeuristicWithBreakdown.
+func RegisterBreakdownHeuristic(id string, params []ParamSpec, factory BreakdownFactory) {
+	register(id, registeredHeuristic{params: params, breakdown: factory})
+}
+
+// RegisterBreakdownHeuristicFunc registers a heuristic scored in parts that takes no parameters.
+func RegisterBreakdownHeuristicFunc(id string, f BreakdownFunc) {
+	RegisterBreakdownHeuristic(id, nil, func(HeuristicParams) BreakdownFunc { return f })
+}
+
+func register(id string, heuristic registeredHeuristic) {
+	for _, spec := range heuristic.params {
+		if _, err := checkParam(spec, spec.Default); err != nil {
+			panic(fmt.Sprintf("heuristic '%s': default %v", id, err))
+		}
+	}
+
+	heuristicRegistry.Lock()
+	defer heuristicRegistry.Unlock()
+	if _, found := heuristicRegistry.heuristics[id]; found {
+		panic(fmt.Sprintf("heuristic '%s' has already been registered", id))
+	}
+	heuristicRegistry.heuristics[id] = heuristic
+}
+
+// RegisteredHeuristics returns the IDs of every registered heuristic in alphabetical order.
+func RegisteredHeuristics() []string {
+	heuristicRegistry.RLock()
+	defer heuristicRegistry.RUnlock()
+	ids := lo.Keys(heuristicRegistry.heuristics)
+	slices.Sort(ids)
+	return ids
+}
+
+// NewRegisteredHeuristic builds the heuristic registered under id with the given weight, name and
+// parameters. Parameters it does not declare, or of the wrong type, are errors.
+func NewRegisteredHeuristic(id string, weight float64, name string, params map[string]any) (WeightedHeuristic, error) {
+	heuristicRegistry.RLock()
+	registered, found := heuristicRegistry.heuristics[id]
+	heuristicRegistry.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("heuristic '%s' is not registered; registered heuristics are %v", id, RegisteredHeuristics())
+	}
+
+	for param := range params {
+		if !lo.ContainsBy(registered.params, func(spec ParamSpec) bool { return spec.Name == param }) {
+			return nil, fmt.Errorf("heuristic '%s' has no parameter '%s'", id, param)
+		}
+	}
+	values := make(map[string]any, len(registered.params))
+	for _, spec := range registered.params {
+		value, given := params[spec.Name]
+		if !given {
+			value = spec.Default
+		}
+		checked, err := checkParam(spec, value)
+		if err != nil {
+			return nil, fmt.Errorf("heuristic '%s': %w", id, err)
+		}
+		values[spec.Name] = checked
+	}
+
+	if registered.breakdown != nil {
+		return NewHeuristicWithBreakdown(weight, name, registered.breakdown(HeuristicParams{values: values})), nil
+	}
+	return NewHeuristic(weight, name, registered.factory(HeuristicParams{values: values})), nil
+}
+
+// checkParam converts a parameter value to its declared kind. Numbers decoded from JSON arrive as
+// float64, so whole floats are accepted for IntParam and ints for FloatParam.
+func checkParam(spec ParamSpec, value any) (any, error) {
+	switch v := value.(type) {
+	case int:
+		switch spec.Kind {
+		case IntParam:
+			return v, nil
+		case FloatParam:
+			return float64(v), nil
+		}
+	case float64:
+		switch spec.Kind {
+		case FloatParam:
+			return v, nil
+		case IntParam:
+			if v == float64(int(v)) {
+				return int(v), nil
+			}
+		}
+	case bool:
+		if spec.Kind == BoolParam {
+			return v, nil
+		}
+	case string:
+		if spec.Kind == StringParam {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("parameter '%s' must be %s, not %v", spec.Name, spec.Kind, value)
+}
@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+)
+
+// lockedSource makes a rand.Source safe to share between concurrent move requests.
+type lockedSource struct {
+	mu     sync.Mutex
+	source rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.source.Int63()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.source.Seed(seed)
+}
+
+// GameSeed derives a decision seed from the game ID, turn and snake ID, so every turn of a
+// recorded game can be replayed to the same move without logging anything beyond the request.
+func GameSeed(snapshot GameSnapshot) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s/%d/%s", snapshot.GameID(), snapshot.Turn(), snapshot.You().ID())
+	return int64(h.Sum64() &^ (1 << 63))
+}
+
+// SetRandSource makes the agent draw its decision seeds from source instead of the global generator.
+func (sa *SnakeAgent) SetRandSource(source rand.Source) {
+	sa.randSource = &lockedSource{source: source}
+}
+
+// nextSeed returns the seed for the next decision: derived from the game when SeedFromGame is set,
+// otherwise drawn from the agent's rand source, or the global generator if it has none.
+func (sa *SnakeAgent) nextSeed(snapshot GameSnapshot) int64 {
+	switch {
+	case sa.SeedFromGame:
+		return GameSeed(snapshot)
+	case sa.randSource != nil:
+		return sa.randSource.Int63()
+	default:
+		return rand.Int63()
+	}
+}
@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+)
+
+// SafetyCheck is a hard constraint on our candidate moves. Moves it vetoes are dropped before
+// scoring, so the softmax can never sample them while a move that passes every check exists.
+type SafetyCheck interface {
+	Name() string
+	Veto(snapshot GameSnapshot, move string) bool
+}
+
+// SafetyCheckFunc reports whether a move of ours must not be played.
+type SafetyCheckFunc func(snapshot GameSnapshot, move string) bool
+
+func NewSafetyCheck(name string, f SafetyCheckFunc) SafetyCheck {
+	return safetyCheckImpl{
+		name: name,
+		f:    f,
+	}
+}
+
+// OutOfBoundsCheck vetoes moves that take our head off the board.
+func OutOfBoundsCheck() SafetyCheck {
+	return NewSafetyCheck("out-of-bounds", outOfBounds)
+}
+
+// SelfCollisionCheck vetoes moves into our own body, other than a tail that moves away this turn.
+func SelfCollisionCheck() SafetyCheck {
+	return NewSafetyCheck("self-collision", selfCollision)
+}
+
+// CertainDeathCheck vetoes moves after which we are eliminated whatever the other snakes do, trying
+// every combination of their forward moves. As in the search, snakes beyond ProximityRadius play only
+// their most likely safe move. Moves off the board or into our own body are vetoed without trying any.
+func (sa *SnakeAgent) CertainDeathCheck() SafetyCheck {
+	return NewSafetyCheck("certain-death", func(snapshot GameSnapshot, move string) bool {
+		if outOfBounds(snapshot, move) || selfCollision(snapshot, move) {
+			return true
+		}
+
+		yourID := snapshot.You().ID()
+		presetMoves := sa.distantSnakeMoves(snapshot)
+		presetMoves[yourID] = rules.SnakeMove{ID: yourID, Move: move}
+		combinations := generateForwardMoveCombinations(snapshot.Snakes(), presetMoves, func(snake SnakeSnapshot) []float64 {
+			return UniformOpponentModel().MoveProbabilities(snapshot, snake)
+		})
+
+		return !lo.SomeBy(combinations, func(combination moveCombination) bool {
+			nextSnapshot, err := snapshot.ApplyMoves(lo.Values(combination.moves))
+			return err == nil && nextSnapshot.You().Alive()
+		})
+	})
+}
+
+func outOfBounds(snapshot GameSnapshot, move string) bool {
+	return !inBounds(snapshot, movePoint(snapshot, snapshot.You().Head(), move))
+}
+
+func selfCollision(snapshot GameSnapshot, move string) bool {
+	you := snapshot.You()
+	body := you.Body()
+	next := movePoint(snapshot, you.Head(), move)
+	tailStacked := len(body) > 1 && body[len(body)-1] == body[len(body)-2]
+	return lo.ContainsBy(body[:len(body)-1], func(p rules.Point) bool { return p == next }) ||
+		(tailStacked && body[len(body)-1] == next)
+}
+
+type safetyCheckImpl struct {
+	name string
+	f    SafetyCheckFunc
+}
+
+func (c safetyCheckImpl) Name() string {
+	return c.name
+}
+
+func (c safetyCheckImpl) Veto(snapshot GameSnapshot, move string) bool {
+	return c.f(snapshot, move)
+}
+
+// filterSafeMoves runs every safety check on the candidate moves, returning the moves to score and,
+// for each vetoed move, the names of the checks that vetoed it. When every move is vetoed, the moves
+// vetoed by the fewest checks are kept as the least bad.
+func filterSafeMoves(snapshot GameSnapshot, checks []SafetyCheck, moves []string) ([]string, map[string][]string) {
+	vetoes := make(map[string][]string)
+	for _, move := range moves {
+		for _, check := range checks {
+			if check.Veto(snapshot, move) {
+				vetoes[move] = append(vetoes[move], check.Name())
+			}
+		}
+	}
+
+	fewestVetoes := lo.Min(lo.Map(moves, func(move string, _ int) int { return len(vetoes[move]) }))
+	return lo.Filter(moves, func(move string, _ int) bool {
+		return len(vetoes[move]) == fewestVetoes
+	}), vetoes
+}
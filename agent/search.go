@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Battle-Bunker/cyphid-snake/lib"
+	"github.com/samber/lo"
+)
+
+// searchNextStates scores the next states of each candidate move by iterative deepening: it searches
+// at depth 1, 2, ... up to maxDepth, and returns the scores from the deepest search that completed
+// before ctx is done, along with that depth. Depth 1 always runs to completion so there is a move to
+// fall back on. The search's work is left in table.
+func (sa *SnakeAgent) searchNextStates(ctx context.Context, table *transpositionTable, nextStatesMap map[string][]nextState, maxDepth int, start time.Time) (map[string][][]float64, int) {
+	var stateScoresMap map[string][][]float64
+	completedDepth := 0
+	for depth := 1; depth <= maxDepth; depth++ {
+		depthCtx := ctx
+		if depth == 1 {
+			depthCtx = context.Background()
+		}
+
+		scores, err := sa.scoreNextStates(depthCtx, table, nextStatesMap, depth, sa.evaluateState)
+		if err != nil {
+			log.Printf("Search at depth %d abandoned after %v: %v", depth, time.Since(start), err)
+			break
+		}
+		stateScoresMap = scores
+		completedDepth = depth
+		log.Printf("Search at depth %d completed after %v", depth, time.Since(start))
+	}
+
+	hits, misses := table.stats()
+	log.Printf("Transposition table: %d hits, %d states scored", hits, misses)
+
+	return stateScoresMap, completedDepth
+}
+
+// searchContext returns a context that expires TimeoutMargin before the game's move timeout,
+// counted from start. Games without a timeout get a context that never expires.
+func (sa *SnakeAgent) searchContext(snapshot GameSnapshot, start time.Time) (context.Context, context.CancelFunc) {
+	if snapshot.Timeout() <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithDeadline(context.Background(), start.Add(snapshot.Timeout()-sa.TimeoutMargin))
+}
+
+// stateEvaluator returns the raw heuristic scores of a snapshot, searched depth further plies; see evaluateState.
+type stateEvaluator func(ctx context.Context, table *transpositionTable, snapshot GameSnapshot, depth int) ([]float64, error)
+
+// scoreNextStates maps each move to the per-heuristic scores of its next states, searched depth-1 further plies
+// by evaluate. Every next state is searched as its own task on the worker pool; each task writes only its own
+// slot, so the results match a sequential search exactly.
+func (sa *SnakeAgent) scoreNextStates(ctx context.Context, table *transpositionTable, nextStatesMap map[string][]nextState, depth int, evaluate stateEvaluator) (map[string][][]float64, error) {
+	type stateRef struct {
+		move  string
+		index int
+	}
+	var refs []stateRef
+	stateScoresMap := make(map[string][][]float64, len(nextStatesMap))
+	for move, states := range nextStatesMap {
+		stateScoresMap[move] = make([][]float64, len(states))
+		for i := range states {
+			refs = append(refs, stateRef{move: move, index: i})
+		}
+	}
+
+	errs := make([]error, len(refs))
+	lib.ParallelFor(len(refs), sa.Workers, func(i int) {
+		ref := refs[i]
+		stateScoresMap[ref.move][ref.index], errs[i] = evaluate(ctx, table, nextStatesMap[ref.move][ref.index].snapshot, depth-1)
+	})
+
+	if err, found := lo.Find(errs, func(err error) bool { return err != nil }); found {
+		return nil, err
+	}
+	return stateScoresMap, nil
+}
+
+// evaluateState returns the raw heuristic scores for a snapshot, aligned with the portfolio.
+// With depth > 0 it looks that many further plies of simultaneous moves ahead: for each of
+// our forward moves the scores of every likely opponent reply are aggregated, and the scores of the
+// move with the best weighted portfolio score are backed up. Leaves are scored by the portfolio.
+// Scores and search results are shared through the transposition table, keyed by the snapshot's hash.
+// The search is abandoned with the context's error as soon as the context is done.
+func (sa *SnakeAgent) evaluateState(ctx context.Context, table *transpositionTable, snapshot GameSnapshot, depth int) ([]float64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	hash := snapshot.Hash()
+	leafScores := func() []float64 {
+		return table.heuristicScores(hash, func() []float64 { return sa.Portfolio.Scores(snapshot) })
+	}
+
+	if depth <= 0 || !snapshot.You().Alive() {
+		return leafScores(), nil
+	}
+	if scores, found := table.searchResult(hash, depth); found {
+		return scores, nil
+	}
+
+	move, bestScores, err := sa.bestMove(ctx, table, snapshot, depth)
+	if err != nil {
+		return nil, err
+	}
+	if bestScores == nil {
+		bestScores = leafScores()
+	}
+	table.storeSearchResult(hash, depth, move, bestScores)
+	return bestScores, nil
+}
+
+// bestMove searches each of our forward moves depth plies deep and returns the move with the best
+// weighted portfolio score, normalized across the moves, with its backed-up heuristic scores. Both are empty if no move has a next state.
+func (sa *SnakeAgent) bestMove(ctx context.Context, table *transpositionTable, snapshot GameSnapshot, depth int) (string, []float64, error) {
+	forwardMoveStrs := snakeMovesToStrings(snapshot.You().ForwardMoves())
+	slices.Sort(forwardMoveStrs)
+
+	var searchedMoves []string
+	var searchedScores [][]float64
+	for _, move := range forwardMoveStrs {
+		nextStates := sa.generateNextStates(snapshot, move, 1)
+		if len(nextStates) == 0 {
+			continue
+		}
+
+		childScores := make([][]float64, len(nextStates))
+		for i, state := range nextStates {
+			scores, err := sa.evaluateState(ctx, table, state.snapshot, depth-1)
+			if err != nil {
+				return "", nil, err
+			}
+			childScores[i] = scores
+		}
+		probabilities := lo.Map(nextStates, func(state nextState, _ int) float64 { return state.probability })
+		moveScores := lo.Map(sa.Portfolio, func(heuristic WeightedHeuristic, i int) float64 {
+			return sa.aggregatorFor(heuristic).Aggregate(lo.Map(childScores, func(scores []float64, _ int) float64 { return scores[i] }), probabilities)
+		})
+
+		searchedMoves = append(searchedMoves, move)
+		searchedScores = append(searchedScores, moveScores)
+	}
+	if len(searchedMoves) == 0 {
+		return "", nil, nil
+	}
+
+	weighted := sa.Portfolio.MoveScores(searchedScores)
+	best := lo.MaxBy(lo.Range(len(searchedMoves)), func(a, b int) bool { return weighted[a] > weighted[b] })
+	return searchedMoves[best], searchedScores[best], nil
+}
@@ -0,0 +1,122 @@
+package agent
+
+import (
+	"io"
+	"log"
+	"reflect"
+	"testing"
+
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/BattlesnakeOfficial/rules/client"
+	"github.com/samber/lo"
+)
+
+func TestMain(m *testing.M) {
+	log.SetOutput(io.Discard)
+	m.Run()
+}
+
+// testSnake describes a snake for testRequest, head first.
+type testSnake struct {
+	id     string
+	color  string
+	health int
+	body   []rules.Point
+}
+
+// testRequest builds a move request for the first snake on an 11x11 standard board with no move timeout,
+// so searches always run to the agent's SearchDepth.
+func testRequest(turn int, food []rules.Point, snakes ...testSnake) *client.SnakeRequest {
+	clientSnakes := lo.Map(snakes, func(snake testSnake, _ int) client.Snake {
+		return client.Snake{
+			ID:             snake.id,
+			Name:           snake.id,
+			Health:         snake.health,
+			Body:           pointsToCoords(snake.body),
+			Head:           pointToCoord(snake.body[0]),
+			Length:         len(snake.body),
+			Customizations: client.Customizations{Color: snake.color},
+		}
+	})
+	return &client.SnakeRequest{
+		Game: client.Game{
+			ID:      "test-game",
+			Ruleset: client.Ruleset{Name: rules.GameTypeStandard, Settings: client.RulesetSettings{FoodSpawnChance: 15, MinimumFood: 1}},
+			Map:     "standard",
+		},
+		Turn:  turn,
+		Board: client.Board{Height: 11, Width: 11, Food: pointsToCoords(food), Snakes: clientSnakes},
+		You:   clientSnakes[0],
+	}
+}
+
+// testPortfolio scores health, length and distance to the nearest food.
+func testPortfolio() HeuristicPortfolio {
+	return NewPortfolio(
+		NewHeuristic(1, "health", func(snapshot GameSnapshot) float64 {
+			return float64(snapshot.You().Health())
+		}),
+		NewHeuristic(2, "length", func(snapshot GameSnapshot) float64 {
+			return float64(snapshot.You().Length())
+		}),
+		NewHeuristic(0.5, "food", func(snapshot GameSnapshot) float64 {
+			if !snapshot.You().Alive() || len(snapshot.Food()) == 0 {
+				return 0
+			}
+			return -float64(lo.Min(lo.Map(snapshot.Food(), func(food rules.Point, _ int) int {
+				return manhattanDistance(snapshot, snapshot.You().Head(), food)
+			})))
+		}),
+	)
+}
+
+func TestDecideWithSeedMatchesSequential(t *testing.T) {
+	requests := map[string]*client.SnakeRequest{
+		"duel": testRequest(3, []rules.Point{{X: 5, Y: 5}, {X: 1, Y: 9}},
+			testSnake{id: "you", color: "#000000", health: 90, body: []rules.Point{{X: 3, Y: 3}, {X: 3, Y: 2}, {X: 3, Y: 1}}},
+			testSnake{id: "them", color: "#FFFFFF", health: 80, body: []rules.Point{{X: 6, Y: 4}, {X: 7, Y: 4}, {X: 8, Y: 4}}},
+		),
+		"crowded": testRequest(20, []rules.Point{{X: 5, Y: 6}, {X: 0, Y: 0}, {X: 10, Y: 10}},
+			testSnake{id: "you", color: "#000000", health: 40, body: []rules.Point{{X: 5, Y: 4}, {X: 5, Y: 3}, {X: 4, Y: 3}, {X: 4, Y: 2}}},
+			testSnake{id: "ally", color: "#000000", health: 70, body: []rules.Point{{X: 2, Y: 7}, {X: 2, Y: 8}, {X: 2, Y: 9}}},
+			testSnake{id: "rival", color: "#FFFFFF", health: 95, body: []rules.Point{{X: 6, Y: 6}, {X: 7, Y: 6}, {X: 7, Y: 7}, {X: 7, Y: 8}}},
+			testSnake{id: "other", color: "#FF0000", health: 60, body: []rules.Point{{X: 8, Y: 2}, {X: 9, Y: 2}, {X: 9, Y: 1}}},
+		),
+	}
+
+	for name, request := range requests {
+		t.Run(name, func(t *testing.T) {
+			decide := func(workers int) MoveDecision {
+				snakeAgent := NewSnakeAgent(testPortfolio(), client.SnakeMetadataResponse{})
+				snakeAgent.SearchDepth = 2
+				snakeAgent.Workers = workers
+				return snakeAgent.DecideWithSeed(NewGameSnapshot(request), 42)
+			}
+
+			sequential := decide(1)
+			parallel := decide(8)
+			if sequential.Depth != 2 {
+				t.Fatalf("sequential search reached depth %d, want 2", sequential.Depth)
+			}
+			if !reflect.DeepEqual(sequential, parallel) {
+				t.Errorf("decisions differ:\n  workers=1: %+v\n  workers=8: %+v", sequential, parallel)
+			}
+		})
+	}
+}
+
+func TestReplayReproducesDecision(t *testing.T) {
+	request := testRequest(3, []rules.Point{{X: 5, Y: 5}},
+		testSnake{id: "you", color: "#000000", health: 90, body: []rules.Point{{X: 3, Y: 3}, {X: 3, Y: 2}, {X: 3, Y: 1}}},
+		testSnake{id: "them", color: "#FFFFFF", health: 80, body: []rules.Point{{X: 6, Y: 4}, {X: 7, Y: 4}, {X: 8, Y: 4}}},
+	)
+	snakeAgent := NewSnakeAgent(testPortfolio(), client.SnakeMetadataResponse{})
+	recorded := snakeAgent.DecideWithSeed(NewGameSnapshot(request), 7)
+
+	// A replaying agent with a shallower limit still searches to the recorded depth.
+	snakeAgent.SearchDepth = 1
+	replayed := snakeAgent.Replay(NewGameSnapshot(request), recorded.Seed, recorded.Depth)
+	if !reflect.DeepEqual(recorded, replayed) {
+		t.Errorf("replay differs:\n  recorded: %+v\n  replayed: %+v", recorded, replayed)
+	}
+}
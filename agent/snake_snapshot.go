@@ -22,6 +22,7 @@ type SnakeSnapshot interface {
 // SnakeSnapshot interface implementation
 type snakeStatsImpl struct {
 	name            string
+	color           string
 	lastShout       string
 	turnLastShouted int
 }
@@ -0,0 +1,239 @@
+package agent
+
+import (
+	"context"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Battle-Bunker/cyphid-snake/lib"
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+	"github.com/samber/mo"
+)
+
+// teamPlanExpiry is how long a plan is kept. Plans are replaced every turn of a running game, so only
+// the last plans of games that have ended get this old.
+const teamPlanExpiry = time.Minute
+
+// teamPlan is the joint move chosen for every snake in our team on one turn. The decision ranks the
+// joint moves, and moves is the one it sampled; both are empty if planning ran out of time.
+type teamPlan struct {
+	jointMoves []map[string]string
+	decision   MoveDecision
+	moves      map[string]string
+	// vetoes maps each teammate's ID to the moves its safety checks vetoed.
+	vetoes map[string]map[string][]string
+	// intents maps each teammate's ID to what it announces, when the agent announces intents.
+	intents map[string]mo.Option[Intent]
+}
+
+// teamPlanCache holds the plan for each team and turn, so that the first teammate to ask plans for
+// everyone and the others, even when their requests arrive concurrently, get the same plan.
+type teamPlanCache struct {
+	mu    sync.Mutex
+	plans map[teamPlanKey]*teamPlanEntry
+}
+
+type teamPlanKey struct {
+	gameID string
+	turn   int
+	team   string
+}
+
+type teamPlanEntry struct {
+	once    sync.Once
+	created time.Time
+	plan    teamPlan
+}
+
+func newTeamPlanCache() *teamPlanCache {
+	return &teamPlanCache{plans: make(map[teamPlanKey]*teamPlanEntry)}
+}
+
+// planFor returns the team's plan for the snapshot's turn, computing it with plan if no teammate has yet.
+// Plans from earlier turns of the same game, and plans older than teamPlanExpiry, are dropped.
+func (c *teamPlanCache) planFor(snapshot GameSnapshot, plan func() teamPlan) teamPlan {
+	teamIDs := lo.Map(snapshot.YourTeam(), func(snake SnakeSnapshot, _ int) string { return snake.ID() })
+	slices.Sort(teamIDs)
+	key := teamPlanKey{gameID: snapshot.GameID(), turn: snapshot.Turn(), team: strings.Join(teamIDs, ",")}
+
+	c.mu.Lock()
+	now := time.Now()
+	for k, entry := range c.plans {
+		if (k.gameID == key.gameID && k.turn < key.turn) || now.Sub(entry.created) > teamPlanExpiry {
+			delete(c.plans, k)
+		}
+	}
+	entry, found := c.plans[key]
+	if !found {
+		entry = &teamPlanEntry{created: now}
+		c.plans[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() { entry.plan = plan() })
+	return entry.plan
+}
+
+// teamDecision answers a teammate's request from the team's joint plan for this turn. If there is
+// no plan for it, it decides alone within what is left of the time since start.
+func (sa *SnakeAgent) teamDecision(snapshot GameSnapshot, start time.Time) MoveDecision {
+	plan := func() teamPlan { return sa.planTeam(snapshot, start) }
+	var chosen teamPlan
+	if sa.teamPlans != nil {
+		chosen = sa.teamPlans.planFor(snapshot, plan)
+	} else {
+		chosen = plan()
+	}
+
+	yourID := snapshot.You().ID()
+	if _, planned := chosen.moves[yourID]; !planned {
+		log.Printf("### Turn %d: no team plan for %s, deciding alone", snapshot.Turn(), snapshot.You().Name())
+		return sa.decide(snapshot, sa.nextSeed(snapshot), start, 0)
+	}
+	log.Printf("### Turn %d: team plan %v, moving %s", snapshot.Turn(), chosen.moves, chosen.moves[yourID])
+	return chosen.decisionFor(yourID)
+}
+
+// decisionFor projects the plan's ranking of joint moves onto one teammate's moves. Each of its moves
+// gets the scores of the best joint move it is part of, and the probability of all of them together.
+func (p teamPlan) decisionFor(id string) MoveDecision {
+	best := make(map[string]int)
+	probabilities := make(map[string]float64)
+	for i, jointMove := range p.jointMoves {
+		move := jointMove[id]
+		if j, found := best[move]; !found || p.decision.Scores[i] > p.decision.Scores[j] {
+			best[move] = i
+		}
+		probabilities[move] += p.decision.Probabilities[i]
+	}
+	moves := lo.Keys(best)
+	slices.Sort(moves)
+	project := func(scores []float64) []float64 {
+		return lo.Map(moves, func(move string, _ int) float64 { return scores[best[move]] })
+	}
+
+	decision := p.decision
+	decision.Moves = moves
+	decision.Vetoes = p.vetoes[id]
+	decision.Heuristics = lo.Map(p.decision.Heuristics, func(breakdown HeuristicBreakdown, _ int) HeuristicBreakdown {
+		breakdown.RawScores = project(breakdown.RawScores)
+		breakdown.NormalizedScores = project(breakdown.NormalizedScores)
+		breakdown.WeightedScores = project(breakdown.WeightedScores)
+		if breakdown.Parts != nil {
+			breakdown.Parts = lo.MapValues(breakdown.Parts, func(scores []float64, _ string) []float64 { return project(scores) })
+		}
+		return breakdown
+	})
+	decision.Scores = project(p.decision.Scores)
+	decision.Probabilities = lo.Map(moves, func(move string, _ int) float64 { return probabilities[move] })
+	decision.Plan = p.moves
+	decision.Move = p.moves[id]
+	decision.Intent = p.intents[id]
+	return decision
+}
+
+// planTeam searches the joint move space of every snake in our team. Each teammate's candidates are
+// its forward moves that pass the safety checks from its own point of view. Every joint move is played
+// against the likely replies of the other snakes, and each resulting state is searched and scored by
+// every heuristic from each teammate's point of view in turn, averaged over the team. The joint moves
+// are then ranked and one is sampled just as single moves are, so teammates never plan into each other.
+// The search deepens iteratively up to SearchDepth within the same deadline as a single snake's, counted
+// from start; if not even depth 1 completes, the plan is empty and every teammate decides alone.
+func (sa *SnakeAgent) planTeam(snapshot GameSnapshot, start time.Time) teamPlan {
+	ctx, cancel := sa.searchContext(snapshot, start)
+	defer cancel()
+
+	team := snapshot.YourTeam()
+	vetoes := make(map[string]map[string][]string, len(team))
+	candidateMoves := lo.Map(team, func(snake SnakeSnapshot, _ int) []rules.SnakeMove {
+		safeMoves, snakeVetoes := filterSafeMoves(snapshot.ForSnake(snake.ID()), sa.SafetyChecks, snakeMovesToStrings(snake.ForwardMoves()))
+		vetoes[snake.ID()] = snakeVetoes
+		return lo.Map(safeMoves, func(move string, _ int) rules.SnakeMove { return rules.SnakeMove{ID: snake.ID(), Move: move} })
+	})
+
+	// joint moves are labelled by their moves in team order, e.g. "up,left"
+	var jointMoves [][]rules.SnakeMove
+	for jointMove := range lib.CartesianProduct(candidateMoves...) {
+		jointMoves = append(jointMoves, jointMove)
+	}
+	labels := lo.Map(jointMoves, func(jointMove []rules.SnakeMove, _ int) string {
+		return strings.Join(snakeMovesToStrings(jointMove), ",")
+	})
+
+	distantMoves := sa.distantSnakeMoves(snapshot)
+	jointStates := make([][]nextState, len(jointMoves))
+	lib.ParallelFor(len(jointMoves), sa.Workers, func(i int) {
+		if ctx.Err() == nil {
+			presetMoves := lo.Assign(distantMoves, lo.SliceToMap(jointMoves[i], func(move rules.SnakeMove) (string, rules.SnakeMove) {
+				return move.ID, move
+			}))
+			jointStates[i] = sa.applyMoveCombinations(snapshot, presetMoves, 1)
+		}
+	})
+	nextStatesMap := lo.SliceToMap(lo.Range(len(labels)), func(i int) (string, []nextState) { return labels[i], jointStates[i] })
+
+	table := newTranspositionTable()
+	evaluate := sa.teamStateEvaluator(team)
+	var stateScoresMap map[string][][]float64
+	completedDepth := 0
+	for depth := 1; depth <= max(sa.SearchDepth, 1) && ctx.Err() == nil; depth++ {
+		scores, err := sa.scoreNextStates(ctx, table, nextStatesMap, depth, evaluate)
+		if err != nil {
+			log.Printf("Team planner abandoned depth %d after %v: %v", depth, time.Since(start), err)
+			break
+		}
+		stateScoresMap = scores
+		completedDepth = depth
+		log.Printf("Team planner completed depth %d after %v", depth, time.Since(start))
+	}
+	if completedDepth == 0 {
+		log.Printf("Team planner ran out of time for %d joint moves after %v", len(jointMoves), time.Since(start))
+		return teamPlan{}
+	}
+
+	decision := sa.sampleMove(snapshot, labels, nextStatesMap, stateScoresMap, sa.nextSeed(snapshot), "")
+	decision.Turn = snapshot.Turn()
+	decision.Depth = completedDepth
+
+	plan := teamPlan{
+		jointMoves: lo.Map(jointMoves, func(jointMove []rules.SnakeMove, _ int) map[string]string {
+			return lo.SliceToMap(jointMove, func(move rules.SnakeMove) (string, string) { return move.ID, move.Move })
+		}),
+		decision: decision,
+		vetoes:   vetoes,
+	}
+	plan.moves = plan.jointMoves[lo.IndexOf(labels, decision.Move)]
+	if sa.AnnounceIntent {
+		plan.intents = make(map[string]mo.Option[Intent], len(team))
+		for _, snake := range team {
+			teammateStates := lo.Map(nextStatesMap[decision.Move], func(state nextState, _ int) nextState {
+				return nextState{snapshot: state.snapshot.ForSnake(snake.ID()), probability: state.probability}
+			})
+			plan.intents[snake.ID()] = sa.intentAfter(ctx, table, snapshot, teammateStates, completedDepth)
+		}
+	}
+
+	log.Printf("Team planner scored %d joint moves for %v", len(jointMoves), lo.Map(team, func(snake SnakeSnapshot, _ int) string { return snake.Name() }))
+	return plan
+}
+
+// teamStateEvaluator evaluates a state from each teammate's point of view in turn, and averages the scores.
+func (sa *SnakeAgent) teamStateEvaluator(team []SnakeSnapshot) stateEvaluator {
+	return func(ctx context.Context, table *transpositionTable, snapshot GameSnapshot, depth int) ([]float64, error) {
+		teamScores := make([][]float64, 0, len(team))
+		for _, snake := range team {
+			scores, err := sa.evaluateState(ctx, table, snapshot.ForSnake(snake.ID()), depth)
+			if err != nil {
+				return nil, err
+			}
+			teamScores = append(teamScores, scores)
+		}
+		return lo.Map(sa.Portfolio, func(_ WeightedHeuristic, h int) float64 {
+			return lo.MeanBy(teamScores, func(scores []float64) float64 { return scores[h] })
+		}), nil
+	}
+}
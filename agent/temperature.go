@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/samber/lo"
+)
+
+// minTemperature keeps the softmax well-defined when a schedule cools all the way down.
+const minTemperature = 0.01
+
+// TemperatureSchedule sets the softmax temperature for each decision. Scores are the aggregate
+// scores of the candidate moves, so a schedule can cool down when one move clearly stands out.
+type TemperatureSchedule interface {
+	Name() string
+	Temperature(snapshot GameSnapshot, scores []float64) float64
+}
+
+// TemperatureFunc returns the softmax temperature for a decision.
+type TemperatureFunc func(snapshot GameSnapshot, scores []float64) float64
+
+func NewTemperatureSchedule(name string, f TemperatureFunc) TemperatureSchedule {
+	return temperatureScheduleImpl{
+		name: name,
+		f:    f,
+	}
+}
+
+// ConstantTemperature always uses the same temperature.
+func ConstantTemperature(temperature float64) TemperatureSchedule {
+	return NewTemperatureSchedule(fmt.Sprintf("constant %.2f", temperature), func(_ GameSnapshot, _ []float64) float64 {
+		return temperature
+	})
+}
+
+// TurnDecayTemperature decays exponentially from initial towards final, halving the gap every halfLife turns.
+func TurnDecayTemperature(initial, final float64, halfLife int) TemperatureSchedule {
+	return NewTemperatureSchedule(fmt.Sprintf("turn decay %.2f->%.2f", initial, final), func(snapshot GameSnapshot, _ []float64) float64 {
+		return final + (initial-final)*math.Pow(0.5, float64(snapshot.Turn())/float64(max(halfLife, 1)))
+	})
+}
+
+// OccupancyDecayTemperature moves linearly from initial on an empty board towards final as snake
+// bodies fill the board.
+func OccupancyDecayTemperature(initial, final float64) TemperatureSchedule {
+	return NewTemperatureSchedule(fmt.Sprintf("occupancy decay %.2f->%.2f", initial, final), func(snapshot GameSnapshot, _ []float64) float64 {
+		occupied := lo.SumBy(snapshot.Snakes(), func(snake SnakeSnapshot) int { return snake.Length() })
+		occupancy := math.Min(1, float64(occupied)/float64(snapshot.Width()*snapshot.Height()))
+		return initial + (final-initial)*occupancy
+	})
+}
+
+// AdaptiveTemperature divides the base schedule's temperature by 1 + gap/gapScale, where gap is how far
+// the best move's score is ahead of the second best. A clear favourite is then played almost surely,
+// while close calls keep exploring.
+func AdaptiveTemperature(base TemperatureSchedule, gapScale float64) TemperatureSchedule {
+	return NewTemperatureSchedule(fmt.Sprintf("adaptive %s", base.Name()), func(snapshot GameSnapshot, scores []float64) float64 {
+		temperature := base.Temperature(snapshot, scores)
+		if len(scores) < 2 || gapScale <= 0 {
+			return temperature
+		}
+		sorted := slices.Clone(scores)
+		slices.Sort(sorted)
+		gap := sorted[len(sorted)-1] - sorted[len(sorted)-2]
+		return temperature / (1 + gap/gapScale)
+	})
+}
+
+type temperatureScheduleImpl struct {
+	name string
+	f    TemperatureFunc
+}
+
+func (t temperatureScheduleImpl) Name() string {
+	return t.name
+}
+
+func (t temperatureScheduleImpl) Temperature(snapshot GameSnapshot, scores []float64) float64 {
+	return math.Max(t.f(snapshot, scores), minTemperature)
+}
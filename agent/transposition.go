@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// transpositionTable caches the work done on each distinct board state while choosing one move:
+// the portfolio's heuristic scores of a state, and the best move and its backed-up scores found by a
+// search from a state to a given depth. Different move combinations often lead to identical boards, so each is scored once.
+// It is safe for concurrent use by the search workers.
+type transpositionTable struct {
+	mu      sync.RWMutex
+	scores  map[uint64][]float64
+	results map[searchKey]searchResult
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type searchKey struct {
+	hash  uint64
+	depth int
+}
+
+type searchResult struct {
+	move   string
+	scores []float64
+}
+
+func newTranspositionTable() *transpositionTable {
+	return &transpositionTable{
+		scores:  make(map[uint64][]float64),
+		results: make(map[searchKey]searchResult),
+	}
+}
+
+// heuristicScores returns the cached heuristic scores of the state, computing them with score on a miss.
+func (t *transpositionTable) heuristicScores(hash uint64, score func() []float64) []float64 {
+	t.mu.RLock()
+	scores, found := t.scores[hash]
+	t.mu.RUnlock()
+	if found {
+		t.hits.Add(1)
+		return scores
+	}
+
+	t.misses.Add(1)
+	scores = score()
+	t.mu.Lock()
+	t.scores[hash] = scores
+	t.mu.Unlock()
+	return scores
+}
+
+// searchResult returns the cached scores of searching the state to the given depth, if there are any.
+func (t *transpositionTable) searchResult(hash uint64, depth int) ([]float64, bool) {
+	t.mu.RLock()
+	result, found := t.results[searchKey{hash: hash, depth: depth}]
+	t.mu.RUnlock()
+	if found {
+		t.hits.Add(1)
+	}
+	return result.scores, found
+}
+
+// deepestBestMove returns the best move from the state found by the deepest cached search up to maxDepth.
+func (t *transpositionTable) deepestBestMove(hash uint64, maxDepth int) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for depth := maxDepth; depth > 0; depth-- {
+		if result, found := t.results[searchKey{hash: hash, depth: depth}]; found && result.move != "" {
+			return result.move, true
+		}
+	}
+	return "", false
+}
+
+func (t *transpositionTable) storeSearchResult(hash uint64, depth int, move string, scores []float64) {
+	t.mu.Lock()
+	t.results[searchKey{hash: hash, depth: depth}] = searchResult{move: move, scores: scores}
+	t.mu.Unlock()
+}
+
+// stats returns the number of lookups served from the table and the number of states scored afresh.
+func (t *transpositionTable) stats() (int64, int64) {
+	return t.hits.Load(), t.misses.Load()
+}
@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"math"
+
+	"github.com/BattlesnakeOfficial/rules"
+)
+
+// Board features that contribute to a snapshot's Zobrist hash.
+const (
+	zobristTurn = iota + 1
+	zobristYou
+	zobristEliminated
+	zobristHealth
+	zobristBody
+	zobristFood
+	zobristHazard
+	zobristExpectedFood
+)
+
+// zobristKey returns the pseudo-random key of one board feature. Instead of drawing keys from a
+// precomputed table, the feature and its coordinates are mixed with splitmix64, which covers any
+// board size and snake length while still giving every feature an independent-looking key.
+func zobristKey(feature int, values ...int) uint64 {
+	key := splitmix64(uint64(feature))
+	for _, value := range values {
+		key = splitmix64(key ^ uint64(value))
+	}
+	return key
+}
+
+func splitmix64(x uint64) uint64 {
+	x += 0x9E3779B97F4A7C15
+	x = (x ^ (x >> 30)) * 0xBF58476D1CE4E5B9
+	x = (x ^ (x >> 27)) * 0x94D049BB133111EB
+	return x ^ (x >> 31)
+}
+
+// zobristPoints hashes a set of points that may repeat (stacked hazards deal extra damage),
+// keying each distinct point by how many times it occurs.
+func zobristPoints(feature int, points []rules.Point) uint64 {
+	counts := make(map[rules.Point]int, len(points))
+	for _, p := range points {
+		counts[p]++
+	}
+	var hash uint64
+	for p, count := range counts {
+		hash ^= zobristKey(feature, p.X, p.Y, count)
+	}
+	return hash
+}
+
+// Hash returns a Zobrist hash of the snapshot: the turn, whose point of view it is, every snake's
+// body, health and elimination, the food, the hazards and any expected food. Snapshots reached
+// through different move orders but with identical boards hash the same.
+func (g *gameSnapshotImpl) Hash() uint64 {
+	hash := zobristKey(zobristTurn, g.boardState.Turn)
+	for i, snake := range g.boardState.Snakes {
+		if snake.ID == g.yourID {
+			hash ^= zobristKey(zobristYou, i)
+		}
+		if snake.EliminatedCause != rules.NotEliminated {
+			hash ^= zobristKey(zobristEliminated, i)
+			continue
+		}
+		hash ^= zobristKey(zobristHealth, i, snake.Health)
+		for j, p := range snake.Body {
+			hash ^= zobristKey(zobristBody, i, j, p.X, p.Y)
+		}
+	}
+	hash ^= zobristPoints(zobristFood, g.boardState.Food)
+	hash ^= zobristPoints(zobristHazard, g.boardState.Hazards)
+	if g.expectedFood > 0 {
+		hash ^= zobristKey(zobristExpectedFood, int(math.Round(g.expectedFood*1000)))
+	}
+	return hash
+}
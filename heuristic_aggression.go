@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/Battle-Bunker/cyphid-snake/agent"
+)
+
+func init() {
+	agent.RegisterBreakdownHeuristicFunc("aggression", HeuristicAggression)
+}
+
+// HeuristicAggression rewards cutting opponents off, with one part per opponent, eliminated or not,
+// labelled "name (ID)" since arena snakes often share a name. Each part grows as the opponent's
+// reachable space shrinks, from 0 when it can reach the whole board, and counts double as that space
+// falls to the opponent's own length, where it is trapped. An eliminated opponent scores the full 200.
+func HeuristicAggression(snapshot agent.GameSnapshot) map[string]float64 {
+	cells := float64(snapshot.Width() * snapshot.Height())
+	parts := make(map[string]float64)
+	for _, opponent := range snapshot.AllOpponents() {
+		space := float64(ReachableSpace(snapshot, opponent))
+		trapped := 1.0
+		if space > 0 {
+			trapped = min(1, float64(opponent.Length())/space)
+		}
+		parts[opponent.Name()+" ("+opponent.ID()+")"] = 100 * (1 - space/cells) * (1 + trapped)
+	}
+	return parts
+}
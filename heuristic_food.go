@@ -0,0 +1,106 @@
+package main
+
+import (
+	"math"
+
+	"github.com/Battle-Bunker/cyphid-snake/agent"
+	"github.com/Battle-Bunker/cyphid-snake/lib/grid"
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+)
+
+func init() {
+	agent.RegisterHeuristicFunc("food", HeuristicFood)
+}
+
+// FoodTarget is one food as seen by one snake.
+type FoodTarget struct {
+	Food rules.Point
+	// Moves is the fewest moves the snake needs to reach the food.
+	Moves int
+	// HealthCost is the health the cheapest path there costs, hazard damage included.
+	HealthCost float64
+	// Won is set when the snake gets there before every opponent, or at the same time as opponents
+	// that are all shorter, so that it would win the head-to-head on the food.
+	Won bool
+}
+
+// HeuristicFood scores how well placed our snake is to eat, from 0 to 100. The score falls as the
+// cheapest food we can win gets further away in health, and falls faster the hungrier we are, so a
+// well-fed snake barely cares and a starving one with no food it can win scores 0. Food the simulation
+// expects to have spawned counts for its chance of being closer; see SpawnedFood.
+func HeuristicFood(snapshot agent.GameSnapshot) float64 {
+	you := snapshot.You()
+	if !you.Alive() {
+		return 0
+	}
+	urgency := 1 - float64(you.Health())/float64(rules.SnakeMaxHealth)
+
+	closeness := 0.0
+	span := float64(snapshot.Width() + snapshot.Height())
+	for _, target := range FoodTargets(snapshot, you) {
+		if target.Won {
+			closeness = math.Max(closeness, 1-(target.HealthCost-1)/span)
+		}
+	}
+	if chance, healthCost := SpawnedFood(snapshot, you); chance > 0 {
+		closeness += chance * math.Max(0, 1-(healthCost-1)/span-closeness)
+	}
+	return 100 * (1 - urgency*(1-closeness))
+}
+
+// FoodTargets returns the food the snake can reach before running out of health. Whether it wins each
+// race is judged against the snakes outside its team.
+func FoodTargets(snapshot agent.GameSnapshot, snake agent.SnakeSnapshot) []FoodTarget {
+	occupancy := agent.BoardOccupancy(snapshot)
+	cost := agent.HazardCost(snapshot)
+	distances := grid.Distances(occupancy, snake.Head())
+	rivals := lo.Map(snapshot.ForSnake(snake.ID()).Opponents(), func(rival agent.SnakeSnapshot, _ int) rivalDistances {
+		return rivalDistances{length: rival.Length(), distances: grid.Distances(occupancy, rival.Head())}
+	})
+
+	var targets []FoodTarget
+	for _, food := range snapshot.Food() {
+		moves := distances.At(food)
+		if moves == grid.Unreachable {
+			continue
+		}
+		path, found := grid.ShortestPath(occupancy, snake.Head(), food, cost)
+		if !found || path.Cost > float64(snake.Health()) {
+			continue
+		}
+		targets = append(targets, FoodTarget{
+			Food:       food,
+			Moves:      moves,
+			HealthCost: path.Cost,
+			Won: lo.EveryBy(rivals, func(rival rivalDistances) bool {
+				rivalMoves := rival.distances.At(food)
+				return rivalMoves == grid.Unreachable || rivalMoves > moves || (rivalMoves == moves && rival.length < snake.Length())
+			}),
+		})
+	}
+	return targets
+}
+
+// SpawnedFood stands in for the food a snapshot simulated with agent.FoodSpawnExpected expects the engine
+// to have spawned but has not placed. The chance is that of at least one such food, taking the expected
+// count as the mean of a Poisson distribution. Spawned food could be on any free cell, so reaching it
+// costs the snake's mean distance to the cells it can reach. The chance is 0 if no food is expected.
+func SpawnedFood(snapshot agent.GameSnapshot, snake agent.SnakeSnapshot) (chance float64, healthCost float64) {
+	expected := snapshot.ExpectedFood()
+	if expected <= 0 {
+		return 0, 0
+	}
+	distances := grid.Distances(agent.BoardOccupancy(snapshot), snake.Head())
+	reachable := distances.Reachable()
+	if len(reachable) == 0 {
+		return 0, 0
+	}
+	healthCost = lo.MeanBy(reachable, func(p rules.Point) float64 { return float64(distances.At(p)) })
+	return 1 - math.Exp(-expected), healthCost
+}
+
+type rivalDistances struct {
+	length    int
+	distances grid.DistanceMap
+}
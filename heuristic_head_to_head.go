@@ -0,0 +1,73 @@
+package main
+
+import (
+	"github.com/Battle-Bunker/cyphid-snake/agent"
+	"github.com/Battle-Bunker/cyphid-snake/lib/grid"
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+)
+
+func init() {
+	agent.RegisterHeuristicFunc("head-to-head", HeuristicHeadToHead)
+}
+
+// HeuristicHeadToHead scores the head-on collisions our snake could be drawn into next turn, from -100
+// to 100. Each cell next to our head that an equal or longer snake could also move into is a threat, and
+// the score drops by the share of our open cells under threat. Against a shorter opponent the score rises
+// by the share of its open cells we could also move into, reaching 100 when every way out runs into us.
+// A snake with no open cell at all scores -100, as does a dead one.
+// Teammates are never targets, but an equal or longer one is a threat, since teammates meeting head-on
+// both die even when the squad settings let them pass through each other's bodies. With those settings,
+// a snake's open cells include its teammates' bodies.
+func HeuristicHeadToHead(snapshot agent.GameSnapshot) float64 {
+	you := snapshot.You()
+	if !you.Alive() {
+		return -100
+	}
+	occupancy := agent.BoardOccupancy(snapshot)
+	allowBodyCollisions := snapshot.Rules().Settings().Bool(rules.ParamAllowBodyCollisions, false)
+	occupancyFor := func(snake agent.SnakeSnapshot) *grid.Occupancy {
+		if !allowBodyCollisions {
+			return occupancy
+		}
+		teammateIDs := lo.Map(snapshot.ForSnake(snake.ID()).Teammates(), func(s agent.SnakeSnapshot, _ int) string { return s.ID() })
+		if len(teammateIDs) == 0 {
+			return occupancy
+		}
+		return agent.BoardOccupancyWithout(snapshot, teammateIDs...)
+	}
+
+	ours := openNeighbours(occupancyFor(you), you.Head())
+	if len(ours) == 0 {
+		return -100 // boxed in, so dead next turn whatever anyone does
+	}
+	reachable := lo.SliceToMap(ours, func(p rules.Point) (rules.Point, bool) { return p, true })
+
+	rivals := append(snapshot.Opponents(), snapshot.Teammates()...)
+	opponentIDs := lo.SliceToMap(snapshot.Opponents(), func(s agent.SnakeSnapshot) (string, bool) { return s.ID(), true })
+
+	threatened := make(map[rules.Point]bool)
+	opportunity := 0.0
+	for _, rival := range rivals {
+		theirs := openNeighbours(occupancyFor(rival), rival.Head())
+		contested := lo.Filter(theirs, func(p rules.Point, _ int) bool { return reachable[p] })
+		switch {
+		case len(contested) == 0:
+		case rival.Length() >= you.Length():
+			for _, p := range contested {
+				threatened[p] = true
+			}
+		case opponentIDs[rival.ID()]:
+			opportunity = max(opportunity, float64(len(contested))/float64(len(theirs)))
+		}
+	}
+	danger := float64(len(threatened)) / float64(len(ours))
+	return 100 * (opportunity - danger)
+}
+
+// openNeighbours returns the cells next to p that will be free after one move.
+func openNeighbours(occupancy *grid.Occupancy, p rules.Point) []rules.Point {
+	return lo.Filter(occupancy.Grid().Neighbours(p), func(next rules.Point, _ int) bool {
+		return !occupancy.Blocked(next, 1)
+	})
+}
@@ -4,6 +4,10 @@ import (
 	"github.com/Battle-Bunker/cyphid-snake/agent"
 )
 
+func init() {
+	agent.RegisterHeuristicFunc("team-health", HeuristicHealth)
+}
+
 // heuristicHealth calculates the sum of health for all snakes in your team,
 // including the player's snake.
 func HeuristicHealth(snapshot agent.GameSnapshot) float64 {
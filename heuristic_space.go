@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/Battle-Bunker/cyphid-snake/agent"
+	"github.com/Battle-Bunker/cyphid-snake/lib/grid"
+)
+
+func init() {
+	agent.RegisterHeuristicFunc("space", HeuristicSpace)
+	agent.RegisterHeuristicFunc("space-per-length", HeuristicSpacePerLength)
+}
+
+// HeuristicSpace is the number of cells our snake can reach from its head.
+func HeuristicSpace(snapshot agent.GameSnapshot) float64 {
+	return float64(ReachableSpace(snapshot, snapshot.You()))
+}
+
+// HeuristicSpacePerLength is the reachable space relative to our length, so that a pocket
+// that would hold a short snake still counts against a long one.
+func HeuristicSpacePerLength(snapshot agent.GameSnapshot) float64 {
+	you := snapshot.You()
+	return float64(ReachableSpace(snapshot, you)) / float64(max(you.Length(), 1))
+}
+
+// ReachableSpace flood-fills from the snake's head and counts the cells it can reach. A cell under a
+// body segment counts as open if that segment will have moved on by the time the snake gets there,
+// so tails that are moving away do not wall the snake in.
+func ReachableSpace(snapshot agent.GameSnapshot, snake agent.SnakeSnapshot) int {
+	if !snake.Alive() {
+		return 0
+	}
+	return len(grid.Distances(agent.BoardOccupancy(snapshot), snake.Head()).Reachable())
+}
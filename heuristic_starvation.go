@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/Battle-Bunker/cyphid-snake/agent"
+	"github.com/Battle-Bunker/cyphid-snake/lib/grid"
+)
+
+func init() {
+	agent.RegisterHeuristicFunc("starvation", HeuristicStarvation)
+}
+
+// HeuristicStarvation sums StarvationPenalty over our team, counting -100 for every eliminated snake in
+// it, ourselves included, so that losing a starving teammate never raises the score.
+func HeuristicStarvation(snapshot agent.GameSnapshot) float64 {
+	total := 0.0
+	for _, snake := range snapshot.AllYourTeam() {
+		if !snake.Alive() {
+			total -= 100
+			continue
+		}
+		total += StarvationPenalty(snapshot, snake)
+	}
+	return total
+}
+
+// StarvationPenalty is -100 / (1 + margin), where margin is the health the snake would have left on
+// reaching its cheapest food: the full -100 at a margin of 0 or less, easing off quickly as the margin
+// grows. When no food is reachable, the snake is assumed to need a trip across the board to food
+// that has yet to spawn. Food the simulation expects to have spawned is weighed in by its chance;
+// see SpawnedFood.
+func StarvationPenalty(snapshot agent.GameSnapshot, snake agent.SnakeSnapshot) float64 {
+	margin, found := StarvationMargin(snapshot, snake)
+	if !found {
+		margin = float64(snake.Health() - (snapshot.Width() + snapshot.Height()))
+	}
+	penalty := -100 / (1 + max(margin, 0))
+	if chance, healthCost := SpawnedFood(snapshot, snake); chance > 0 {
+		spawnedMargin := max(margin, float64(snake.Health())-healthCost)
+		penalty = (1-chance)*penalty + chance*-100/(1+max(spawnedMargin, 0))
+	}
+	return penalty
+}
+
+// StarvationMargin returns the snake's health less the health the cheapest path to any food costs,
+// counting 1 per move and the game's hazard damage on hazard cells. It reports false if no food can be
+// reached at all.
+func StarvationMargin(snapshot agent.GameSnapshot, snake agent.SnakeSnapshot) (float64, bool) {
+	occupancy := agent.BoardOccupancy(snapshot)
+	cost := agent.HazardCost(snapshot)
+
+	found := false
+	cheapest := 0.0
+	for _, food := range snapshot.Food() {
+		path, ok := grid.ShortestPath(occupancy, snake.Head(), food, cost)
+		if ok && (!found || path.Cost < cheapest) {
+			cheapest, found = path.Cost, true
+		}
+	}
+	return float64(snake.Health()) - cheapest, found
+}
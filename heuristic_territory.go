@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/Battle-Bunker/cyphid-snake/agent"
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+)
+
+func init() {
+	agent.RegisterHeuristicFunc("territory", HeuristicTerritory)
+}
+
+// HeuristicTerritory is our team's share of the board less the opponents' share, in percent of the
+// board's cells, with territory assigned by Territory.
+func HeuristicTerritory(snapshot agent.GameSnapshot) float64 {
+	territory := Territory(snapshot)
+	cellsOf := func(snakes []agent.SnakeSnapshot) int {
+		return lo.SumBy(snakes, func(snake agent.SnakeSnapshot) int { return territory[snake.ID()] })
+	}
+	cells := snapshot.Width() * snapshot.Height()
+	return 100 * float64(cellsOf(snapshot.YourTeam())-cellsOf(snapshot.Opponents())) / float64(cells)
+}
+
+// Territory grows every alive snake's region from its head at the same pace, one cell per turn, and
+// counts the cells each snake reaches first. Snakes that reach a cell on the same turn settle it as a
+// head-to-head collision would: the longest takes it, and equal lengths leave it to nobody. A claimed
+// cell stops the other snakes' regions, and body cells open up once their segment has moved on.
+func Territory(snapshot agent.GameSnapshot) map[string]int {
+	occupancy := agent.BoardOccupancy(snapshot)
+	board := occupancy.Grid()
+	snakes := snapshot.Snakes()
+	lengths := lo.SliceToMap(snakes, func(snake agent.SnakeSnapshot) (string, int) { return snake.ID(), snake.Length() })
+
+	territory := make(map[string]int)
+	claimed := make(map[rules.Point]bool)
+	frontiers := make(map[string][]rules.Point)
+	for _, snake := range snakes {
+		claimed[snake.Head()] = true
+		frontiers[snake.ID()] = []rules.Point{snake.Head()}
+	}
+
+	for turn := 1; len(frontiers) > 0; turn++ {
+		claimants := make(map[rules.Point][]string)
+		for id, frontier := range frontiers {
+			for _, p := range frontier {
+				for _, next := range board.Neighbours(p) {
+					if claimed[next] || occupancy.Blocked(next, turn) || lo.Contains(claimants[next], id) {
+						continue
+					}
+					claimants[next] = append(claimants[next], id)
+				}
+			}
+		}
+
+		frontiers = make(map[string][]rules.Point)
+		for p, ids := range claimants {
+			claimed[p] = true
+			longest := lo.MaxBy(ids, func(a, b string) bool { return lengths[a] > lengths[b] })
+			if lo.CountBy(ids, func(id string) bool { return lengths[id] == lengths[longest] }) > 1 {
+				continue
+			}
+			territory[longest]++
+			frontiers[longest] = append(frontiers[longest], p)
+		}
+	}
+	return territory
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/Battle-Bunker/cyphid-snake/agent"
+	"github.com/Battle-Bunker/cyphid-snake/lib/grid"
+	"github.com/BattlesnakeOfficial/rules"
+	"github.com/samber/lo"
+)
+
+// TrapClosingTurns is how many moves ahead the trap heuristic looks for opponents closing a chokepoint,
+// unless its closingTurns parameter says otherwise.
+const TrapClosingTurns = 3
+
+func init() {
+	agent.RegisterHeuristic("trap", []agent.ParamSpec{
+		{Name: "closingTurns", Kind: agent.IntParam, Default: TrapClosingTurns},
+	}, func(params agent.HeuristicParams) agent.HeuristicFunc {
+		closingTurns := params.Int("closingTurns")
+		return func(snapshot agent.GameSnapshot) float64 {
+			return trapPenalty(snapshot, closingTurns)
+		}
+	})
+}
+
+// trapPenalty penalizes our snake for being shut into a region too small to hold it, from 0 when
+// the region fits our length down to -100 for no room at all; see TrapRegion.
+func trapPenalty(snapshot agent.GameSnapshot, closingTurns int) float64 {
+	you := snapshot.You()
+	if !you.Alive() {
+		return -100
+	}
+	region := TrapRegion(snapshot, you, closingTurns)
+	return -100 * max(0, 1-float64(region)/float64(you.Length()))
+}
+
+// TrapRegion returns the size of the region the snake is committed to. That is the space it can reach,
+// with tails freeing up as they move, which already accounts for a pocket behind the snake's own body.
+// It shrinks further when a chokepoint, a cell whose loss splits the open board, can be closed by an
+// opponent head within closingTurns moves and no later than the snake itself could get through it;
+// the region is then what remains on the snake's side.
+func TrapRegion(snapshot agent.GameSnapshot, snake agent.SnakeSnapshot, closingTurns int) int {
+	occupancy := agent.BoardOccupancy(snapshot)
+	ours := grid.Distances(occupancy, snake.Head())
+	region := len(ours.Reachable())
+
+	rivals := snapshot.ForSnake(snake.ID()).Opponents()
+	if len(rivals) == 0 {
+		return region
+	}
+	theirs := grid.Distances(occupancy, lo.Map(rivals, func(rival agent.SnakeSnapshot, _ int) rules.Point { return rival.Head() })...)
+
+	for _, chokepoint := range grid.ArticulationPoints(occupancy, 1, snake.Head()) {
+		ourMoves, theirMoves := ours.At(chokepoint), theirs.At(chokepoint)
+		if chokepoint == snake.Head() || ourMoves == grid.Unreachable || theirMoves == grid.Unreachable ||
+			theirMoves > closingTurns || theirMoves > ourMoves {
+			continue
+		}
+		closed := occupancy.Clone()
+		closed.Block(chokepoint)
+		region = min(region, len(grid.Distances(closed, snake.Head()).Reachable()))
+	}
+	return region
+}
@@ -0,0 +1,98 @@
+package grid
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+
+	"github.com/BattlesnakeOfficial/rules"
+)
+
+// CostFunc returns the cost of moving onto p. Costs must be at least 1, the cost of a plain move,
+// so that the distance to the goal never overestimates; return math.Inf(1) for a cell that cannot
+// be entered at all. Hazards, for example, cost 1 plus the damage they deal.
+type CostFunc func(p rules.Point) float64
+
+// UnitCost makes every move cost 1.
+func UnitCost(rules.Point) float64 {
+	return 1
+}
+
+// Path is a route between two cells, from the cell after the start up to and including the goal.
+type Path struct {
+	Points []rules.Point
+	Cost   float64
+}
+
+// ShortestPath finds the cheapest path from start to goal with A*, avoiding cells that are blocked
+// on the turn the path would enter them. It reports false if the goal cannot be reached.
+func ShortestPath(occupancy *Occupancy, start, goal rules.Point, cost CostFunc) (Path, bool) {
+	g := occupancy.Grid()
+	if !g.Contains(start) || !g.Contains(goal) {
+		return Path{}, false
+	}
+
+	costs := make([]float64, g.Size())
+	steps := make([]int, g.Size())
+	previous := make([]int, g.Size())
+	for i := range costs {
+		costs[i] = math.Inf(1)
+		previous[i] = -1
+	}
+	costs[g.index(start)] = 0
+
+	open := &pathQueue{{index: g.index(start), estimate: float64(g.Distance(start, goal))}}
+	for open.Len() > 0 {
+		current := heap.Pop(open).(pathNode)
+		if current.index == g.index(goal) {
+			return Path{Points: walkBack(g, previous, g.index(start), current.index), Cost: costs[current.index]}, true
+		}
+		p := g.point(current.index)
+		if current.estimate > costs[current.index]+float64(g.Distance(p, goal)) {
+			continue // a cheaper route to this cell was found after it was queued
+		}
+		for _, next := range g.Neighbours(p) {
+			i := g.index(next)
+			if occupancy.Blocked(next, steps[current.index]+1) {
+				continue
+			}
+			nextCost := costs[current.index] + cost(next)
+			if nextCost >= costs[i] || math.IsInf(nextCost, 1) {
+				continue
+			}
+			costs[i] = nextCost
+			steps[i] = steps[current.index] + 1
+			previous[i] = current.index
+			heap.Push(open, pathNode{index: i, estimate: nextCost + float64(g.Distance(next, goal))})
+		}
+	}
+	return Path{}, false
+}
+
+func walkBack(g Grid, previous []int, start, goal int) []rules.Point {
+	var points []rules.Point
+	for i := goal; i != start; i = previous[i] {
+		points = append(points, g.point(i))
+	}
+	slices.Reverse(points)
+	return points
+}
+
+type pathNode struct {
+	index    int
+	estimate float64
+}
+
+// pathQueue is a min-heap of nodes by estimated total cost.
+type pathQueue []pathNode
+
+func (q pathQueue) Len() int           { return len(q) }
+func (q pathQueue) Less(i, j int) bool { return q[i].estimate < q[j].estimate }
+func (q pathQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x any)        { *q = append(*q, x.(pathNode)) }
+func (q *pathQueue) Pop() any {
+	old := *q
+	node := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return node
+}
@@ -0,0 +1,73 @@
+package grid
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/BattlesnakeOfficial/rules"
+)
+
+func TestShortestPath(t *testing.T) {
+	hazardCost := func(hazards ...rules.Point) CostFunc {
+		return func(p rules.Point) float64 {
+			if slices.Contains(hazards, p) {
+				return 15
+			}
+			return 1
+		}
+	}
+	wall := func(p rules.Point) float64 {
+		if p.X == 2 {
+			return math.Inf(1)
+		}
+		return 1
+	}
+
+	tests := []struct {
+		name      string
+		grid      Grid
+		block     map[rules.Point]int
+		cost      CostFunc
+		wantFound bool
+		wantCost  float64
+		avoid     []rules.Point
+	}{
+		{"open board", New(5, 3, false), nil, UnitCost, true, 4, nil},
+		{"wrapped board", New(5, 3, true), nil, UnitCost, true, 1, nil},
+		{"segment gone by the time it is reached", New(5, 1, false), map[rules.Point]int{{X: 2, Y: 0}: 2}, UnitCost, true, 4, nil},
+		{"segment still there in a corridor", New(5, 1, false), map[rules.Point]int{{X: 2, Y: 0}: 3}, UnitCost, false, 0, nil},
+		{"detour round a segment still there", New(5, 3, false), map[rules.Point]int{{X: 2, Y: 0}: 5}, UnitCost, true, 6, []rules.Point{{X: 2, Y: 0}}},
+		{"detour round a hazard", New(5, 3, false), nil, hazardCost(rules.Point{X: 2, Y: 0}), true, 6, []rules.Point{{X: 2, Y: 0}}},
+		{"hazard cheaper than the detour", New(5, 3, false), nil, hazardCost(rules.Point{X: 2, Y: 0}, rules.Point{X: 2, Y: 1}), true, 8, []rules.Point{{X: 2, Y: 0}, {X: 2, Y: 1}}},
+		{"hazard with no way round", New(5, 1, false), nil, hazardCost(rules.Point{X: 2, Y: 0}), true, 18, nil},
+		{"impassable cost", New(5, 3, false), nil, wall, false, 0, nil},
+	}
+	start, goal := rules.Point{X: 0, Y: 0}, rules.Point{X: 4, Y: 0}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occupancy := NewOccupancy(tt.grid)
+			for p, turns := range tt.block {
+				occupancy.BlockFor(p, turns)
+			}
+			path, found := ShortestPath(occupancy, start, goal, tt.cost)
+			if found != tt.wantFound {
+				t.Fatalf("found = %v, want %v", found, tt.wantFound)
+			}
+			if !found {
+				return
+			}
+			if path.Cost != tt.wantCost {
+				t.Errorf("cost = %v, want %v (path %v)", path.Cost, tt.wantCost, path.Points)
+			}
+			if path.Points[len(path.Points)-1] != goal {
+				t.Errorf("path %v does not end at %v", path.Points, goal)
+			}
+			for _, p := range tt.avoid {
+				if slices.Contains(path.Points, p) {
+					t.Errorf("path %v goes through %v", path.Points, p)
+				}
+			}
+		})
+	}
+}
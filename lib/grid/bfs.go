@@ -0,0 +1,65 @@
+package grid
+
+import (
+	"github.com/BattlesnakeOfficial/rules"
+)
+
+// Unreachable is the distance of a cell a search never reached.
+const Unreachable = -1
+
+// DistanceMap holds the number of moves from the nearest source to every cell of a grid.
+type DistanceMap struct {
+	grid      Grid
+	distances []int
+}
+
+// At returns the distance to p, or Unreachable.
+func (d DistanceMap) At(p rules.Point) int {
+	if !d.grid.Contains(p) {
+		return Unreachable
+	}
+	return d.distances[d.grid.index(p)]
+}
+
+// Reachable returns the reached cells other than the sources, in board order.
+func (d DistanceMap) Reachable() []rules.Point {
+	var points []rules.Point
+	for i, distance := range d.distances {
+		if distance > 0 {
+			points = append(points, d.grid.point(i))
+		}
+	}
+	return points
+}
+
+// Distances runs a breadth-first search from the sources. A cell is entered on the turn equal to its
+// distance, so a cell that is still blocked on that turn cannot be entered then, though it may still be
+// reached later by a longer way round. Sources are at distance 0 whether or not they are blocked.
+func Distances(occupancy *Occupancy, sources ...rules.Point) DistanceMap {
+	g := occupancy.Grid()
+	distances := make([]int, g.Size())
+	for i := range distances {
+		distances[i] = Unreachable
+	}
+
+	var queue []rules.Point
+	for _, source := range sources {
+		if g.Contains(source) && distances[g.index(source)] == Unreachable {
+			distances[g.index(source)] = 0
+			queue = append(queue, source)
+		}
+	}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		distance := distances[g.index(p)]
+		for _, next := range g.Neighbours(p) {
+			if distances[g.index(next)] != Unreachable || occupancy.Blocked(next, distance+1) {
+				continue
+			}
+			distances[g.index(next)] = distance + 1
+			queue = append(queue, next)
+		}
+	}
+	return DistanceMap{grid: g, distances: distances}
+}
@@ -0,0 +1,135 @@
+package grid
+
+import (
+	"github.com/BattlesnakeOfficial/rules"
+)
+
+// Components labels the open cells of a grid by the connected region they belong to.
+type Components struct {
+	grid   Grid
+	labels []int
+	sizes  []int
+}
+
+// Label returns the index of p's region, or -1 if p is not open.
+func (c Components) Label(p rules.Point) int {
+	if !c.grid.Contains(p) {
+		return -1
+	}
+	return c.labels[c.grid.index(p)]
+}
+
+// Size returns the number of cells in p's region, or 0 if p is not open.
+func (c Components) Size(p rules.Point) int {
+	if label := c.Label(p); label >= 0 {
+		return c.sizes[label]
+	}
+	return 0
+}
+
+// Count returns the number of regions.
+func (c Components) Count() int {
+	return len(c.sizes)
+}
+
+// ConnectedComponents splits the cells that are open on the given turn into connected regions.
+// The alsoOpen points count as open regardless, e.g. to include a snake's own head.
+func ConnectedComponents(occupancy *Occupancy, turn int, alsoOpen ...rules.Point) Components {
+	g := occupancy.Grid()
+	open := openCells(occupancy, turn, alsoOpen)
+	components := Components{grid: g, labels: make([]int, g.Size())}
+	for i := range components.labels {
+		components.labels[i] = -1
+	}
+
+	for i := range open {
+		if !open[i] || components.labels[i] >= 0 {
+			continue
+		}
+		label := len(components.sizes)
+		components.sizes = append(components.sizes, 0)
+		components.labels[i] = label
+		queue := []int{i}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			components.sizes[label]++
+			for _, next := range g.Neighbours(g.point(current)) {
+				j := g.index(next)
+				if open[j] && components.labels[j] < 0 {
+					components.labels[j] = label
+					queue = append(queue, j)
+				}
+			}
+		}
+	}
+	return components
+}
+
+// ArticulationPoints returns the open cells whose loss would split their region in two, in board
+// order: the chokepoints a snake would cut off space behind by passing through.
+// The alsoOpen points count as open regardless, as for ConnectedComponents.
+func ArticulationPoints(occupancy *Occupancy, turn int, alsoOpen ...rules.Point) []rules.Point {
+	g := occupancy.Grid()
+	open := openCells(occupancy, turn, alsoOpen)
+	discovered := make([]int, g.Size())
+	low := make([]int, g.Size())
+	isArticulation := make([]bool, g.Size())
+	time := 0
+
+	// Tarjan's algorithm: a cell is an articulation point if some subtree of its depth-first search
+	// cannot reach back above it without going through it.
+	var visit func(i, parent int)
+	visit = func(i, parent int) {
+		time++
+		discovered[i], low[i] = time, time
+		children := 0
+		for _, next := range g.Neighbours(g.point(i)) {
+			j := g.index(next)
+			switch {
+			case !open[j] || j == parent:
+				continue
+			case discovered[j] > 0:
+				low[i] = min(low[i], discovered[j])
+			default:
+				children++
+				visit(j, i)
+				low[i] = min(low[i], low[j])
+				if parent >= 0 && low[j] >= discovered[i] {
+					isArticulation[i] = true
+				}
+			}
+		}
+		if parent < 0 && children > 1 {
+			isArticulation[i] = true
+		}
+	}
+
+	for i := range open {
+		if open[i] && discovered[i] == 0 {
+			visit(i, -1)
+		}
+	}
+
+	var points []rules.Point
+	for i, articulation := range isArticulation {
+		if articulation {
+			points = append(points, g.point(i))
+		}
+	}
+	return points
+}
+
+func openCells(occupancy *Occupancy, turn int, alsoOpen []rules.Point) []bool {
+	g := occupancy.Grid()
+	open := make([]bool, g.Size())
+	for i := range open {
+		open[i] = !occupancy.Blocked(g.point(i), turn)
+	}
+	for _, p := range alsoOpen {
+		if g.Contains(p) {
+			open[g.index(p)] = true
+		}
+	}
+	return open
+}
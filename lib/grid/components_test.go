@@ -0,0 +1,71 @@
+package grid
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/BattlesnakeOfficial/rules"
+)
+
+// corridor is a 7x3 board split by a wall down the middle column, open only at {3, 1}.
+func corridor(wrapped bool) *Occupancy {
+	occupancy := NewOccupancy(New(7, 3, wrapped))
+	occupancy.Block(rules.Point{X: 3, Y: 0}, rules.Point{X: 3, Y: 2})
+	return occupancy
+}
+
+func TestArticulationPoints(t *testing.T) {
+	tests := []struct {
+		name      string
+		occupancy *Occupancy
+		alsoOpen  []rules.Point
+		want      []rules.Point
+	}{
+		{"open board", NewOccupancy(New(3, 3, false)), nil, nil},
+		{"corridor", corridor(false), nil, []rules.Point{{X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}}},
+		{"corridor with a way round the wrapped edge", corridor(true), nil, nil},
+		{"u-shaped path", func() *Occupancy {
+			occupancy := NewOccupancy(New(3, 3, false))
+			occupancy.Block(rules.Point{X: 1, Y: 1}, rules.Point{X: 1, Y: 2})
+			return occupancy
+		}(), nil, []rules.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}, {X: 2, Y: 1}}},
+		{"blocked head counted as open", func() *Occupancy {
+			occupancy := corridor(false)
+			occupancy.Block(rules.Point{X: 3, Y: 1})
+			return occupancy
+		}(), []rules.Point{{X: 3, Y: 1}}, []rules.Point{{X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ArticulationPoints(tt.occupancy, 0, tt.alsoOpen...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ArticulationPoints = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectedComponents(t *testing.T) {
+	open := corridor(false)
+	if components := ConnectedComponents(open, 0); components.Count() != 1 || components.Size(rules.Point{X: 0, Y: 0}) != 19 {
+		t.Errorf("open corridor: %d regions, %d cells with {0, 0}; want 1 region of 19", components.Count(), components.Size(rules.Point{X: 0, Y: 0}))
+	}
+
+	closed := open.Clone()
+	closed.Block(rules.Point{X: 3, Y: 1})
+	components := ConnectedComponents(closed, 0)
+	if components.Count() != 2 {
+		t.Fatalf("closed corridor: %d regions, want 2", components.Count())
+	}
+	left, right := rules.Point{X: 0, Y: 0}, rules.Point{X: 6, Y: 2}
+	if components.Label(left) == components.Label(right) || components.Size(left) != 9 || components.Size(right) != 9 {
+		t.Errorf("closed corridor: regions %d and %d of %d and %d cells, want two of 9",
+			components.Label(left), components.Label(right), components.Size(left), components.Size(right))
+	}
+	if components.Label(rules.Point{X: 3, Y: 1}) != -1 {
+		t.Errorf("blocked cell has region %d, want -1", components.Label(rules.Point{X: 3, Y: 1}))
+	}
+
+	if ConnectedComponents(open, 0).Count() != 1 {
+		t.Errorf("blocking the clone changed the original")
+	}
+}
@@ -0,0 +1,90 @@
+// Package grid provides board geometry and path finding over rules.Point cells: occupancy,
+// breadth-first distances, A* with per-cell costs, connected components and articulation points.
+// Every function follows the grid's edges, wrapping around them on a wrapped board.
+package grid
+
+import (
+	"github.com/BattlesnakeOfficial/rules"
+)
+
+// Grid is the shape of a board. On a wrapped board, moving off one edge comes back on the opposite one.
+type Grid struct {
+	Width   int
+	Height  int
+	Wrapped bool
+}
+
+func New(width, height int, wrapped bool) Grid {
+	return Grid{Width: width, Height: height, Wrapped: wrapped}
+}
+
+// Size is the number of cells on the board.
+func (g Grid) Size() int {
+	return g.Width * g.Height
+}
+
+// Contains reports whether p is on the board.
+func (g Grid) Contains(p rules.Point) bool {
+	return p.X >= 0 && p.X < g.Width && p.Y >= 0 && p.Y < g.Height
+}
+
+// Move returns the point reached by moving from p in the given direction. The point may be off
+// the board unless the board wraps.
+func (g Grid) Move(p rules.Point, move string) rules.Point {
+	switch move {
+	case rules.MoveUp:
+		p.Y++
+	case rules.MoveDown:
+		p.Y--
+	case rules.MoveLeft:
+		p.X--
+	case rules.MoveRight:
+		p.X++
+	}
+	if g.Wrapped {
+		p.X = (p.X + g.Width) % g.Width
+		p.Y = (p.Y + g.Height) % g.Height
+	}
+	return p
+}
+
+// Neighbours returns the cells on the board one move away from p, in up, down, left, right order.
+func (g Grid) Neighbours(p rules.Point) []rules.Point {
+	neighbours := make([]rules.Point, 0, 4)
+	for _, move := range []string{rules.MoveUp, rules.MoveDown, rules.MoveLeft, rules.MoveRight} {
+		if next := g.Move(p, move); g.Contains(next) {
+			neighbours = append(neighbours, next)
+		}
+	}
+	return neighbours
+}
+
+// Distance is the Manhattan distance between two points, measured around the edges on a wrapped board.
+func (g Grid) Distance(a, b rules.Point) int {
+	dx := max(a.X-b.X, b.X-a.X)
+	dy := max(a.Y-b.Y, b.Y-a.Y)
+	if g.Wrapped {
+		dx = min(dx, g.Width-dx)
+		dy = min(dy, g.Height-dy)
+	}
+	return dx + dy
+}
+
+// Points returns every cell on the board, row by row from the bottom left.
+func (g Grid) Points() []rules.Point {
+	points := make([]rules.Point, 0, g.Size())
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			points = append(points, rules.Point{X: x, Y: y})
+		}
+	}
+	return points
+}
+
+func (g Grid) index(p rules.Point) int {
+	return p.Y*g.Width + p.X
+}
+
+func (g Grid) point(i int) rules.Point {
+	return rules.Point{X: i % g.Width, Y: i / g.Width}
+}
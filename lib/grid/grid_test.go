@@ -0,0 +1,100 @@
+package grid
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/BattlesnakeOfficial/rules"
+)
+
+func TestMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrapped bool
+		from    rules.Point
+		move    string
+		want    rules.Point
+	}{
+		{"inside", false, rules.Point{X: 5, Y: 5}, rules.MoveUp, rules.Point{X: 5, Y: 6}},
+		{"off the left edge", false, rules.Point{X: 0, Y: 5}, rules.MoveLeft, rules.Point{X: -1, Y: 5}},
+		{"wrapped left edge", true, rules.Point{X: 0, Y: 5}, rules.MoveLeft, rules.Point{X: 10, Y: 5}},
+		{"wrapped right edge", true, rules.Point{X: 10, Y: 5}, rules.MoveRight, rules.Point{X: 0, Y: 5}},
+		{"wrapped top edge", true, rules.Point{X: 5, Y: 10}, rules.MoveUp, rules.Point{X: 5, Y: 0}},
+		{"wrapped bottom edge", true, rules.Point{X: 5, Y: 0}, rules.MoveDown, rules.Point{X: 5, Y: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(11, 11, tt.wrapped).Move(tt.from, tt.move); got != tt.want {
+				t.Errorf("Move(%v, %s) = %v, want %v", tt.from, tt.move, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrapped bool
+		p       rules.Point
+		want    []rules.Point
+	}{
+		{"middle", false, rules.Point{X: 5, Y: 5}, []rules.Point{{X: 5, Y: 6}, {X: 5, Y: 4}, {X: 4, Y: 5}, {X: 6, Y: 5}}},
+		{"corner", false, rules.Point{X: 0, Y: 0}, []rules.Point{{X: 0, Y: 1}, {X: 1, Y: 0}}},
+		{"wrapped corner", true, rules.Point{X: 0, Y: 0}, []rules.Point{{X: 0, Y: 1}, {X: 0, Y: 10}, {X: 10, Y: 0}, {X: 1, Y: 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(11, 11, tt.wrapped).Neighbours(tt.p); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Neighbours(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrapped bool
+		a, b    rules.Point
+		want    int
+	}{
+		{"same cell", false, rules.Point{X: 3, Y: 3}, rules.Point{X: 3, Y: 3}, 0},
+		{"opposite corners", false, rules.Point{X: 0, Y: 0}, rules.Point{X: 10, Y: 10}, 20},
+		{"wrapped opposite corners", true, rules.Point{X: 0, Y: 0}, rules.Point{X: 10, Y: 10}, 2},
+		{"wrapped across the middle", true, rules.Point{X: 0, Y: 0}, rules.Point{X: 5, Y: 6}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(11, 11, tt.wrapped).Distance(tt.a, tt.b); got != tt.want {
+				t.Errorf("Distance(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistances(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrapped bool
+		block   map[rules.Point]int
+		to      rules.Point
+		want    int
+	}{
+		{"open board", false, nil, rules.Point{X: 4, Y: 0}, 4},
+		{"wrapped board", true, nil, rules.Point{X: 4, Y: 0}, 1},
+		{"around a wall", false, map[rules.Point]int{{X: 1, Y: 0}: Forever, {X: 1, Y: 1}: Forever}, rules.Point{X: 2, Y: 0}, 6},
+		{"through a segment that has moved on", false, map[rules.Point]int{{X: 1, Y: 0}: 1, {X: 1, Y: 1}: Forever}, rules.Point{X: 2, Y: 0}, 2},
+		{"walled in", false, map[rules.Point]int{{X: 1, Y: 0}: Forever, {X: 0, Y: 1}: Forever}, rules.Point{X: 2, Y: 0}, Unreachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occupancy := NewOccupancy(New(5, 5, tt.wrapped))
+			for p, turns := range tt.block {
+				occupancy.BlockFor(p, turns)
+			}
+			if got := Distances(occupancy, rules.Point{X: 0, Y: 0}).At(tt.to); got != tt.want {
+				t.Errorf("distance to %v = %d, want %d", tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package grid
+
+import (
+	"math"
+	"slices"
+
+	"github.com/BattlesnakeOfficial/rules"
+)
+
+// Occupancy records which cells of a grid are blocked and for how long. A snake's body segment
+// blocks its cell only until the snake has moved past it, so each cell holds the number of turns
+// until it is free: zero for an open cell, and Forever for a cell that never opens.
+type Occupancy struct {
+	grid      Grid
+	freeAfter []int
+}
+
+// Forever is the number of turns a permanently blocked cell stays blocked.
+const Forever = math.MaxInt
+
+func NewOccupancy(grid Grid) *Occupancy {
+	return &Occupancy{grid: grid, freeAfter: make([]int, grid.Size())}
+}
+
+func (o *Occupancy) Grid() Grid {
+	return o.grid
+}
+
+// Block blocks the points for good.
+func (o *Occupancy) Block(points ...rules.Point) {
+	for _, p := range points {
+		o.BlockFor(p, Forever)
+	}
+}
+
+// BlockFor blocks p for the next turns turns. A cell blocked more than once stays blocked for the
+// longest of them. Points off the board are ignored.
+func (o *Occupancy) BlockFor(p rules.Point, turns int) {
+	if o.grid.Contains(p) {
+		o.freeAfter[o.grid.index(p)] = max(o.freeAfter[o.grid.index(p)], turns)
+	}
+}
+
+// FreeAfter returns the number of turns until p is free; points off the board are never free.
+func (o *Occupancy) FreeAfter(p rules.Point) int {
+	if !o.grid.Contains(p) {
+		return Forever
+	}
+	return o.freeAfter[o.grid.index(p)]
+}
+
+// Blocked reports whether p is blocked on the given turn, counting from now as turn 0.
+func (o *Occupancy) Blocked(p rules.Point, turn int) bool {
+	return o.FreeAfter(p) > turn
+}
+
+// Clone returns a copy that can be blocked further without changing o.
+func (o *Occupancy) Clone() *Occupancy {
+	return &Occupancy{grid: o.grid, freeAfter: slices.Clone(o.freeAfter)}
+}
@@ -26,15 +26,19 @@ func Softmax(inputs []float64) []float64 {
 }
 
 func SampleFromWeights(weights []float64) int {
-		r := rand.Float64()
-		var cumulativeProb float64
-		for i, weight := range weights {
-			cumulativeProb += weight
-			if r <= cumulativeProb {
-				return i
-			}
+	return SampleFromWeightsWithDraw(weights, rand.Float64())
+}
+
+// SampleFromWeightsWithDraw returns the index whose cumulative weight first reaches the draw r in [0, 1).
+func SampleFromWeightsWithDraw(weights []float64, r float64) int {
+	var cumulativeProb float64
+	for i, weight := range weights {
+		cumulativeProb += weight
+		if r <= cumulativeProb {
+			return i
 		}
-		return len(weights) - 1
+	}
+	return len(weights) - 1
 }
 
 func SoftmaxSample(inputs []float64) int {
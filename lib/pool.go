@@ -0,0 +1,33 @@
+package lib
+
+import "sync"
+
+// ParallelFor calls f for every index in [0, n) on at most workers goroutines, returning once every
+// call has finished. With workers <= 1 the calls run in order on the calling goroutine.
+// Callers get deterministic results by having each call write only to its own index.
+func ParallelFor(n int, workers int, f func(i int)) {
+	if workers <= 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			f(i)
+		}
+		return
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, n); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				f(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+}
@@ -0,0 +1,96 @@
+package lib
+
+import (
+	"math"
+	"slices"
+
+	"github.com/samber/lo"
+)
+
+// WeightedMean returns the mean of values, each counted in proportion to its weight.
+func WeightedMean(values []float64, weights []float64) float64 {
+	totalWeight := lo.Sum(weights)
+	if len(values) == 0 || totalWeight <= 0 {
+		return 0
+	}
+	sum := 0.0
+	for i, value := range values {
+		sum += value * weights[i]
+	}
+	return sum / totalWeight
+}
+
+// WeightedPercentile returns the p-th percentile (0..100) of weighted values, interpolating linearly
+// between ranks. With equal weights it matches the usual linear-interpolation percentile.
+func WeightedPercentile(values []float64, weights []float64, p float64) float64 {
+	sorted, sortedWeights := sortByValue(values, weights)
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	// each value sits at the fraction of total weight that precedes it, scaled so the last value sits at 1
+	span := lo.Sum(sortedWeights) - sortedWeights[len(sortedWeights)-1]
+	if span <= 0 {
+		return sorted[len(sorted)-1]
+	}
+	target := math.Max(0, math.Min(1, p/100))
+
+	cumulative := 0.0
+	for i := 0; i < len(sorted)-1; i++ {
+		lowerPos := cumulative / span
+		upperPos := (cumulative + sortedWeights[i]) / span
+		if target <= upperPos {
+			if upperPos == lowerPos {
+				return sorted[i]
+			}
+			return sorted[i] + (sorted[i+1]-sorted[i])*(target-lowerPos)/(upperPos-lowerPos)
+		}
+		cumulative += sortedWeights[i]
+	}
+	return sorted[len(sorted)-1]
+}
+
+// WeightedConditionalValueAtRisk returns the weighted mean of the worst (lowest) alpha fraction (0..1]
+// of the total weight, splitting the value on the boundary. Tiny alphas degrade to the minimum.
+func WeightedConditionalValueAtRisk(values []float64, weights []float64, alpha float64) float64 {
+	sorted, sortedWeights := sortByValue(values, weights)
+	totalWeight := lo.Sum(sortedWeights)
+	if len(sorted) == 0 || totalWeight <= 0 {
+		return 0
+	}
+
+	tailWeight := math.Max(math.Min(1, alpha), 1e-9) * totalWeight
+	remaining := tailWeight
+	sum := 0.0
+	for i, value := range sorted {
+		take := math.Min(sortedWeights[i], remaining)
+		sum += value * take
+		remaining -= take
+		if remaining <= 0 {
+			break
+		}
+	}
+	return sum / (tailWeight - remaining)
+}
+
+// sortByValue returns copies of values and their weights ordered by ascending value,
+// dropping values without positive weight.
+func sortByValue(values []float64, weights []float64) ([]float64, []float64) {
+	indices := lo.Filter(lo.Range(len(values)), func(i int, _ int) bool {
+		return weights[i] > 0
+	})
+	slices.SortStableFunc(indices, func(a, b int) int {
+		switch {
+		case values[a] < values[b]:
+			return -1
+		case values[a] > values[b]:
+			return 1
+		}
+		return 0
+	})
+	return lo.Map(indices, func(i int, _ int) float64 { return values[i] }),
+		lo.Map(indices, func(i int, _ int) float64 { return weights[i] })
+}
@@ -1,26 +1,27 @@
 package main
 
 import (
+	"flag"
+	"log"
+
 	"github.com/Battle-Bunker/cyphid-snake/agent"
 	"github.com/Battle-Bunker/cyphid-snake/server"
-	"github.com/BattlesnakeOfficial/rules/client"
 )
 
 func main() {
+	configPath := flag.String("config", "config.json", "JSON file describing the snake's metadata, portfolio, temperature and aggregation")
+	flag.Parse()
 
-	metadata := client.SnakeMetadataResponse{
-		APIVersion: "1",
-		Author:     "zuthan",
-		Color:      "#FF7F7F",
-		Head:       "evil",
-		Tail:       "nr-booster",
+	config, err := agent.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
 	}
+	snakeAgent, err := agent.NewSnakeAgentFromConfig(config)
+	if err != nil {
+		log.Fatalf("Failed to build snake from %s: %v", *configPath, err)
+	}
+	log.Printf("Loaded %s with heuristics %v", *configPath, agent.RegisteredHeuristics())
 
-	portfolio := agent.NewPortfolio(
-		agent.NewHeuristic(1.0, "team-health", HeuristicHealth),
-	)
-
-	snakeAgent := agent.NewSnakeAgentWithTemp(portfolio, 5.0, metadata)
 	server := server.NewServer(snakeAgent)
 
 	server.Start()